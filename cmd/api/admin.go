@@ -0,0 +1,177 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerAdminRoutes(e *echo.Group) {
+	admin := e.Group("/admin", app.requireAdmin)
+	admin.POST("/members/purge", app.purgeMembers)
+	admin.GET("/members/:id/sensitive-access", app.getSensitiveAccessLog)
+	admin.POST("/members/normalize-phones", app.normalizeMemberPhones)
+}
+
+// requireAdmin only lets through requests bearing the configured admin token.
+func (app *application) requireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return requireToken(app.admin.token, "Admin")(next)
+}
+
+// requireToken only lets through requests bearing the token. Every request is
+// refused when the token is empty.
+func requireToken(want, name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if want == "" {
+				return &echo.HTTPError{
+					Code:    http.StatusForbidden,
+					Message: name + " endpoints are disabled",
+				}
+			}
+
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+				return &echo.HTTPError{
+					Code:    http.StatusUnauthorized,
+					Message: "Invalid " + strings.ToLower(name) + " token",
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+type purgeMembersResponse struct {
+	ArchivedBefore time.Time `json:"archived_before"`
+	Purged         int64     `json:"purged"`
+}
+
+// purgeMembers permanently removes the members archived longer than the retention period.
+func (app *application) purgeMembers(c echo.Context) error {
+	archivedBefore := time.Now().Add(-app.members.retentionPeriod)
+
+	ids, err := app.store.PurgeMembers(c.Request().Context(), archivedBefore)
+	if err != nil {
+		app.logger.WriteError("Error purging members", err, map[string]interface{}{
+			"archived_before": archivedBefore,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to purge members",
+		}
+	}
+	purged := int64(len(ids))
+
+	for _, id := range ids {
+		if err := app.removeMemberPhoto(c.Request().Context(), id); err != nil {
+			app.logger.WriteError("Error deleting photo of purged member", err, map[string]interface{}{
+				"id": id,
+			})
+		}
+	}
+
+	app.logger.WriteInfo("Purged archived members", map[string]interface{}{
+		"archived_before": archivedBefore,
+		"purged":          purged,
+	})
+
+	return c.JSON(http.StatusOK, purgeMembersResponse{
+		ArchivedBefore: archivedBefore,
+		Purged:         purged,
+	})
+}
+
+type phoneIssueResponse struct {
+	MemberID      uuid.UUID  `json:"member_id"`
+	Phone         string     `json:"phone"`
+	Normalized    string     `json:"normalized,omitempty"`
+	ConflictsWith *uuid.UUID `json:"conflicts_with,omitempty"`
+}
+
+type normalizeMemberPhonesResponse struct {
+	DryRun     bool                 `json:"dry_run"`
+	Normalized int64                `json:"normalized"`
+	Unchanged  int                  `json:"unchanged"`
+	Invalid    []phoneIssueResponse `json:"invalid"`
+	Conflicts  []phoneIssueResponse `json:"conflicts"`
+}
+
+// normalizeMemberPhones rewrites the stored phone numbers of all members in E.164
+// form, reading numbers without a country code as numbers of the default region.
+// It is run once after upgrading to normalized phone numbers, and again whenever
+// numbers were left over. Numbers that are not valid, or that would clash with
+// another member's number, are left as they are and reported for staff to fix,
+// e.g. by merging duplicates. With dry_run=true nothing is changed.
+func (app *application) normalizeMemberPhones(c echo.Context) error {
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+
+	phones, err := app.store.GetMemberPhones(c.Request().Context())
+	if err != nil {
+		app.logger.WriteError("Error getting member phones", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get member phones",
+		}
+	}
+
+	n := model.NormalizePhones(phones, app.members.phoneRegion)
+	resp := normalizeMemberPhonesResponse{
+		DryRun:     dryRun,
+		Normalized: int64(len(n.Changes)),
+		Unchanged:  n.Unchanged,
+		Invalid:    make([]phoneIssueResponse, len(n.Invalid)),
+		Conflicts:  make([]phoneIssueResponse, len(n.Conflicts)),
+	}
+	for i, issue := range n.Invalid {
+		resp.Invalid[i] = newPhoneIssueResponse(issue)
+	}
+	for i, issue := range n.Conflicts {
+		resp.Conflicts[i] = newPhoneIssueResponse(issue)
+	}
+
+	if dryRun || len(n.Changes) == 0 {
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	if resp.Normalized, err = app.store.UpdateMemberPhones(c.Request().Context(), n.Changes); err != nil {
+		app.logger.WriteError("Error normalizing member phones", err, nil)
+		switch {
+		case errors.Is(err, postgres.ErrMemberAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Member phones changed, try again",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to normalize member phones",
+			}
+		}
+	}
+
+	app.logger.WriteInfo("Normalized member phones", map[string]interface{}{
+		"normalized": resp.Normalized,
+		"invalid":    len(resp.Invalid),
+		"conflicts":  len(resp.Conflicts),
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+func newPhoneIssueResponse(issue model.PhoneIssue) phoneIssueResponse {
+	return phoneIssueResponse{
+		MemberID:      issue.MemberID,
+		Phone:         issue.Phone,
+		Normalized:    issue.Normalized,
+		ConflictsWith: issue.ConflictsWith,
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerBatchRoutes(e *echo.Group) {
+	e.POST("/sports/:id/batches", app.addBatch)
+	e.GET("/sports/:id/batches", app.getSportBatches)
+	e.GET("/batches/:id", app.getBatchByID)
+	e.PATCH("/batches/:id", app.updateBatch)
+	e.DELETE("/batches/:id", app.deleteBatch)
+}
+
+// batchSlot is the API form of model.BatchSlot, e.g. {"weekday": "monday",
+// "start": "17:00", "end": "18:30"}.
+type batchSlot struct {
+	Weekday string `json:"weekday"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+func newBatchSlots(slots []model.BatchSlot) []batchSlot {
+	resp := make([]batchSlot, len(slots))
+	for i, slot := range slots {
+		resp[i] = batchSlot{
+			Weekday: strings.ToLower(slot.Weekday.String()),
+			Start:   slot.Start.String(),
+			End:     slot.End.String(),
+		}
+	}
+	return resp
+}
+
+// parseBatchSlots converts the slots to their model form.
+func parseBatchSlots(slots []batchSlot) ([]model.BatchSlot, error) {
+	var verr model.ValidationError
+	parsed := make([]model.BatchSlot, len(slots))
+	for i, slot := range slots {
+		weekday, ok := model.ParseWeekday(slot.Weekday)
+		if !ok {
+			verr.Add("slots", model.CodeInvalidValue, "Weekday must be the name of a day of the week")
+			break
+		}
+		start, err := model.ParseTimeOfDay(slot.Start)
+		if err != nil {
+			verr.Add("slots", model.CodeInvalidFormat, "Start must be a time in the form HH:MM")
+			break
+		}
+		end, err := model.ParseTimeOfDay(slot.End)
+		if err != nil {
+			verr.Add("slots", model.CodeInvalidFormat, "End must be a time in the form HH:MM")
+			break
+		}
+		parsed[i] = model.BatchSlot{Weekday: weekday, Start: start, End: end}
+	}
+	return parsed, verr.Err()
+}
+
+type addBatchRequest struct {
+	Name     string           `json:"name"`
+	Capacity int              `json:"capacity"`
+	MinAge   *int             `json:"min_age"`
+	MaxAge   *int             `json:"max_age"`
+	Level    model.BatchLevel `json:"level"`
+	Slots    []batchSlot      `json:"slots"`
+}
+
+type batchResponse struct {
+	ID        uuid.UUID        `json:"id"`
+	SportID   uuid.UUID        `json:"sport_id"`
+	Name      string           `json:"name"`
+	Capacity  int              `json:"capacity"`
+	Enrolled  int              `json:"enrolled"`
+	Available int              `json:"available"`
+	MinAge    *int             `json:"min_age"`
+	MaxAge    *int             `json:"max_age"`
+	Level     model.BatchLevel `json:"level"`
+	Slots     []batchSlot      `json:"slots"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+func newBatchResponse(batch *model.Batch) batchResponse {
+	return batchResponse{
+		ID:        batch.ID,
+		SportID:   batch.SportID,
+		Name:      batch.Name,
+		Capacity:  batch.Capacity,
+		Enrolled:  batch.Enrolled,
+		Available: max(batch.Capacity-batch.Enrolled, 0),
+		MinAge:    batch.MinAge,
+		MaxAge:    batch.MaxAge,
+		Level:     batch.Level,
+		Slots:     newBatchSlots(batch.Slots),
+		CreatedAt: batch.CreatedAt,
+	}
+}
+
+func (app *application) addBatch(c echo.Context) error {
+	sportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid sport ID",
+		}
+	}
+
+	var req addBatchRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	slots, err := parseBatchSlots(req.Slots)
+	if err != nil {
+		return app.validationError(err)
+	}
+	batch := &model.Batch{
+		SportID:  sportID,
+		Name:     req.Name,
+		Capacity: req.Capacity,
+		MinAge:   req.MinAge,
+		MaxAge:   req.MaxAge,
+		Level:    req.Level,
+		Slots:    slots,
+	}
+	if err := batch.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddBatch(c.Request().Context(), batch); err != nil {
+		app.logger.WriteError("Error adding batch", err, map[string]interface{}{
+			"sport_id": sportID,
+			"name":     batch.Name,
+		})
+		return app.batchError(err, "Failed to add batch")
+	}
+
+	return c.JSON(http.StatusCreated, newBatchResponse(batch))
+}
+
+func (app *application) getSportBatches(c echo.Context) error {
+	sportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid sport ID",
+		}
+	}
+
+	batches, err := app.store.GetBatchesBySportID(c.Request().Context(), sportID)
+	if err != nil {
+		app.logger.WriteError("Error getting batches", err, map[string]interface{}{
+			"sport_id": sportID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get batches",
+		}
+	}
+
+	resp := make([]batchResponse, len(batches))
+	for i, batch := range batches {
+		resp[i] = newBatchResponse(batch)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (app *application) getBatchByID(c echo.Context) error {
+	batch, err := app.batch(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newBatchResponse(batch))
+}
+
+// updateBatchRequest changes the batch. Slots replace the current ones as a
+// whole. The sport of a batch cannot be changed.
+type updateBatchRequest struct {
+	Name     *string           `json:"name"`
+	Capacity *int              `json:"capacity"`
+	MinAge   *int              `json:"min_age"`
+	MaxAge   *int              `json:"max_age"`
+	Level    *model.BatchLevel `json:"level"`
+	Slots    *[]batchSlot      `json:"slots"`
+}
+
+func (app *application) updateBatch(c echo.Context) error {
+	batch, err := app.batch(c)
+	if err != nil {
+		return err
+	}
+
+	var req updateBatchRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error binding request", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to bind request",
+		}
+	}
+
+	if req.Name != nil {
+		batch.Name = *req.Name
+	}
+	if req.Capacity != nil {
+		batch.Capacity = *req.Capacity
+	}
+	if req.MinAge != nil {
+		batch.MinAge = req.MinAge
+	}
+	if req.MaxAge != nil {
+		batch.MaxAge = req.MaxAge
+	}
+	if req.Level != nil {
+		batch.Level = *req.Level
+	}
+	if req.Slots != nil {
+		slots, err := parseBatchSlots(*req.Slots)
+		if err != nil {
+			return app.validationError(err)
+		}
+		batch.Slots = slots
+	}
+	if err := batch.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.UpdateBatch(c.Request().Context(), batch); err != nil {
+		app.logger.WriteError("Error updating batch", err, map[string]interface{}{
+			"id": batch.ID,
+		})
+		return app.batchError(err, "Failed to update batch")
+	}
+
+	return c.JSON(http.StatusOK, newBatchResponse(batch))
+}
+
+// deleteBatch removes a batch that no membership has ever been enrolled in.
+func (app *application) deleteBatch(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid batch ID",
+		}
+	}
+
+	if err := app.store.DeleteBatch(c.Request().Context(), id); err != nil {
+		app.logger.WriteError("Error deleting batch", err, map[string]interface{}{
+			"id": id,
+		})
+		return app.batchError(err, "Failed to delete batch")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// batch loads the batch addressed by the :id path parameter.
+func (app *application) batch(c echo.Context) (*model.Batch, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid batch ID",
+		}
+	}
+
+	batch, err := app.store.GetBatchByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting batch", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, app.batchError(err, "Failed to get batch")
+	}
+
+	return batch, nil
+}
+
+// batchError maps the errors of the batch store to responses, with msg for
+// unexpected errors.
+func (app *application) batchError(err error, msg string) error {
+	switch {
+	case errors.Is(err, postgres.ErrBatchNotFound):
+		return &echo.HTTPError{
+			Code:    http.StatusNotFound,
+			Message: "Batch not found",
+		}
+	case errors.Is(err, postgres.ErrBatchReferenceNotFound):
+		return &echo.HTTPError{
+			Code:    http.StatusNotFound,
+			Message: "Sport not found",
+		}
+	case errors.Is(err, postgres.ErrBatchAlreadyExists):
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Batch already exists",
+		}
+	case errors.Is(err, postgres.ErrBatchCapacityBelowTotal):
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Capacity is below the number of enrolled memberships",
+		}
+	case errors.Is(err, postgres.ErrBatchInUse):
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Batch has memberships",
+		}
+	case errors.Is(err, postgres.ErrMissingRequiredField):
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Missing required fields",
+		}
+	default:
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: msg,
+		}
+	}
+}
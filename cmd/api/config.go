@@ -1,10 +1,53 @@
 package main
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/spf13/viper"
+)
 
 type config struct {
 	DBURL   string `mapstructure:"DB_URL"`
 	APIAddr string `mapstructure:"API_ADDR"`
+
+	MembershipWorkerInterval time.Duration `mapstructure:"MEMBERSHIP_WORKER_INTERVAL"`
+	MembershipGracePeriod    time.Duration `mapstructure:"MEMBERSHIP_GRACE_PERIOD"`
+
+	ClubName      string `mapstructure:"CLUB_NAME"`
+	InvoicePrefix string `mapstructure:"INVOICE_PREFIX"`
+
+	// AdminToken authorizes the admin endpoints. They are disabled when it is empty.
+	AdminToken            string        `mapstructure:"ADMIN_TOKEN"`
+	MemberRetentionPeriod time.Duration `mapstructure:"MEMBER_RETENTION_PERIOD"`
+	// PhoneDefaultRegion is the region, e.g. IN, of phone numbers typed without a country code.
+	PhoneDefaultRegion string `mapstructure:"PHONE_DEFAULT_REGION"`
+
+	// SensitiveDataToken authorizes access to emergency contacts and medical notes,
+	// which are encrypted with EncryptionKey, a base64 encoded 32 byte key. They are
+	// disabled when the token is empty.
+	SensitiveDataToken string `mapstructure:"SENSITIVE_DATA_TOKEN"`
+	EncryptionKey      string `mapstructure:"ENCRYPTION_KEY"`
+
+	// BlobDir is the directory member photos are stored in. PhotoMaxSize is the
+	// largest upload accepted, in bytes.
+	BlobDir      string `mapstructure:"BLOB_DIR"`
+	PhotoMaxSize int64  `mapstructure:"PHOTO_MAX_SIZE"`
+
+	// Mailer is "outbox", which writes emails to MailOutboxDir instead of sending
+	// them, or "smtp".
+	Mailer        string `mapstructure:"MAILER"`
+	MailFrom      string `mapstructure:"MAIL_FROM"`
+	MailOutboxDir string `mapstructure:"MAIL_OUTBOX_DIR"`
+	SMTPAddr      string `mapstructure:"SMTP_ADDR"`
+	SMTPUsername  string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword  string `mapstructure:"SMTP_PASSWORD"`
+
+	// EmailTokenSecret signs the links confirming a new email address. PublicURL
+	// is where members reach the API, e.g. https://club.example.com.
+	EmailTokenSecret string        `mapstructure:"EMAIL_TOKEN_SECRET"`
+	EmailChangeTTL   time.Duration `mapstructure:"EMAIL_CHANGE_TTL"`
+	PublicURL        string        `mapstructure:"PUBLIC_URL"`
 }
 
 func newConfig(path string) (*config, error) {
@@ -12,6 +55,27 @@ func newConfig(path string) (*config, error) {
 	viper.SetConfigName("app")
 	viper.SetConfigType("env")
 
+	viper.SetDefault("MEMBERSHIP_WORKER_INTERVAL", time.Hour)
+	viper.SetDefault("MEMBERSHIP_GRACE_PERIOD", 7*24*time.Hour)
+	viper.SetDefault("CLUB_NAME", "Sports Club")
+	viper.SetDefault("INVOICE_PREFIX", "INV")
+	viper.SetDefault("ADMIN_TOKEN", "")
+	viper.SetDefault("MEMBER_RETENTION_PERIOD", 365*24*time.Hour)
+	viper.SetDefault("PHONE_DEFAULT_REGION", model.DefaultPhoneRegion)
+	viper.SetDefault("SENSITIVE_DATA_TOKEN", "")
+	viper.SetDefault("ENCRYPTION_KEY", "")
+	viper.SetDefault("BLOB_DIR", "data/blobs")
+	viper.SetDefault("PHOTO_MAX_SIZE", 5<<20)
+	viper.SetDefault("MAILER", "outbox")
+	viper.SetDefault("MAIL_FROM", "members@localhost")
+	viper.SetDefault("MAIL_OUTBOX_DIR", "data/outbox")
+	viper.SetDefault("SMTP_ADDR", "")
+	viper.SetDefault("SMTP_USERNAME", "")
+	viper.SetDefault("SMTP_PASSWORD", "")
+	viper.SetDefault("EMAIL_TOKEN_SECRET", "")
+	viper.SetDefault("EMAIL_CHANGE_TTL", 24*time.Hour)
+	viper.SetDefault("PUBLIC_URL", "")
+
 	// Enable reading from environment variables
 	viper.AutomaticEnv()
 
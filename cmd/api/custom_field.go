@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// registerCustomFieldRoutes registers the definitions of the club's custom member
+// fields. Anyone may read them, but only admins may change them.
+func (app *application) registerCustomFieldRoutes(e *echo.Group) {
+	e.GET("/custom-fields", app.getCustomFields)
+	e.GET("/custom-fields/:id", app.getCustomFieldByID)
+	e.POST("/custom-fields", app.addCustomField, app.requireAdmin)
+	e.PATCH("/custom-fields/:id", app.updateCustomField, app.requireAdmin)
+	e.DELETE("/custom-fields/:id", app.deleteCustomField, app.requireAdmin)
+}
+
+type addCustomFieldRequest struct {
+	Name          string                `json:"name"`
+	Type          model.CustomFieldType `json:"type"`
+	Required      bool                  `json:"required"`
+	AllowedValues []string              `json:"allowed_values"`
+}
+
+type customFieldResponse struct {
+	ID            uuid.UUID             `json:"id"`
+	Name          string                `json:"name"`
+	Type          model.CustomFieldType `json:"type"`
+	Required      bool                  `json:"required"`
+	AllowedValues []string              `json:"allowed_values"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+func newCustomFieldResponse(field *model.CustomField) customFieldResponse {
+	allowed := field.AllowedValues
+	if allowed == nil {
+		allowed = []string{}
+	}
+	return customFieldResponse{
+		ID:            field.ID,
+		Name:          field.Name,
+		Type:          field.Type,
+		Required:      field.Required,
+		AllowedValues: allowed,
+		CreatedAt:     field.CreatedAt,
+	}
+}
+
+func (app *application) addCustomField(c echo.Context) error {
+	var req addCustomFieldRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	field := &model.CustomField{
+		Name:          req.Name,
+		Type:          req.Type,
+		Required:      req.Required,
+		AllowedValues: req.AllowedValues,
+	}
+	if err := field.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddCustomField(c.Request().Context(), field); err != nil {
+		app.logger.WriteError("Error adding custom field", err, map[string]interface{}{
+			"name": field.Name,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrCustomFieldAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Custom field already exists",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to add custom field",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newCustomFieldResponse(field))
+}
+
+func (app *application) getCustomFields(c echo.Context) error {
+	fields, err := app.customFields(c)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]customFieldResponse, len(fields))
+	for i, field := range fields {
+		resp[i] = newCustomFieldResponse(field)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (app *application) getCustomFieldByID(c echo.Context) error {
+	field, err := app.customField(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newCustomFieldResponse(field))
+}
+
+// updateCustomFieldRequest changes the constraints of a custom field. Its name
+// and type cannot be changed, as the values members already have depend on them.
+type updateCustomFieldRequest struct {
+	Required      *bool     `json:"required"`
+	AllowedValues *[]string `json:"allowed_values"`
+}
+
+func (app *application) updateCustomField(c echo.Context) error {
+	field, err := app.customField(c)
+	if err != nil {
+		return err
+	}
+
+	var req updateCustomFieldRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error binding request", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to bind request",
+		}
+	}
+
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.AllowedValues != nil {
+		field.AllowedValues = *req.AllowedValues
+	}
+	if err := field.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.UpdateCustomField(c.Request().Context(), field); err != nil {
+		app.logger.WriteError("Error updating custom field", err, map[string]interface{}{
+			"id": field.ID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrCustomFieldNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Custom field not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update custom field",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newCustomFieldResponse(field))
+}
+
+// deleteCustomField removes the custom field together with its values on members.
+func (app *application) deleteCustomField(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid custom field ID",
+		}
+	}
+
+	if err := app.store.DeleteCustomField(c.Request().Context(), id); err != nil {
+		app.logger.WriteError("Error deleting custom field", err, map[string]interface{}{
+			"id": id,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrCustomFieldNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Custom field not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete custom field",
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// customField loads the custom field addressed by the :id path parameter.
+func (app *application) customField(c echo.Context) (*model.CustomField, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid custom field ID",
+		}
+	}
+
+	field, err := app.store.GetCustomFieldByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting custom field", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrCustomFieldNotFound):
+			return nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Custom field not found",
+			}
+		default:
+			return nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get custom field",
+			}
+		}
+	}
+
+	return field, nil
+}
+
+// customFields loads the definitions of every custom field, which member
+// validation and filtering depend on.
+func (app *application) customFields(c echo.Context) ([]*model.CustomField, error) {
+	fields, err := app.store.GetCustomFields(c.Request().Context())
+	if err != nil {
+		app.logger.WriteError("Error getting custom fields", err, nil)
+		return nil, &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get custom fields",
+		}
+	}
+	return fields, nil
+}
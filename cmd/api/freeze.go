@@ -0,0 +1,236 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerFreezeRoutes(e *echo.Group) {
+	e.POST("/memberships/:id/freeze", app.freezeMembership)
+	e.POST("/memberships/:id/unfreeze", app.unfreezeMembership)
+	e.GET("/memberships/:id/freezes", app.getMembershipFreezes)
+}
+
+type freezeMembershipRequest struct {
+	Reason string `json:"reason"`
+}
+
+type getFreezeResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	MembershipID uuid.UUID  `json:"membership_id"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	Days         int        `json:"days"`
+	Reason       string     `json:"reason"`
+}
+
+type freezeMembershipResponse struct {
+	Membership getMembershipResponse `json:"membership"`
+	Freeze     getFreezeResponse     `json:"freeze"`
+}
+
+func newFreezeResponse(freeze *model.Freeze) getFreezeResponse {
+	return getFreezeResponse{
+		ID:           freeze.ID,
+		MembershipID: freeze.MembershipID,
+		StartDate:    freeze.StartDate,
+		EndDate:      freeze.EndDate,
+		Days:         freeze.Days,
+		Reason:       freeze.Reason,
+	}
+}
+
+func (app *application) freezeMembership(c echo.Context) error {
+	var req freezeMembershipRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	membership, err := app.membership(c)
+	if err != nil {
+		return err
+	}
+
+	transition, err := membership.Transition(model.MembershipFrozen, actor(c), req.Reason)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Only active memberships can be frozen",
+		}
+	}
+
+	plan, err := app.membershipPlan(c, membership)
+	if err != nil {
+		return err
+	}
+
+	freezes, err := app.store.GetFreezesByMembershipID(c.Request().Context(), membership.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting freezes", err, map[string]interface{}{
+			"membership_id": membership.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get freezes",
+		}
+	}
+
+	now := time.Now()
+	if err := plan.CanFreeze(model.FrozenDaysInYear(freezes, now.Year())); err != nil {
+		app.logger.WriteError("Freeze refused", err, map[string]interface{}{
+			"membership_id": membership.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+		}
+	}
+
+	freeze := &model.Freeze{
+		StartDate: now,
+		Reason:    req.Reason,
+	}
+	if err := app.store.FreezeMembership(c.Request().Context(), membership, freeze, transition); err != nil {
+		app.logger.WriteError("Error freezing membership", err, map[string]interface{}{
+			"membership_id": membership.ID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrMembershipStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership status changed, try again",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to freeze membership",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, freezeMembershipResponse{
+		Membership: newMembershipResponse(membership),
+		Freeze:     newFreezeResponse(freeze),
+	})
+}
+
+func (app *application) unfreezeMembership(c echo.Context) error {
+	membership, err := app.membership(c)
+	if err != nil {
+		return err
+	}
+
+	if membership.Status != model.MembershipFrozen {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Membership is not frozen",
+		}
+	}
+
+	transition, err := membership.Transition(model.MembershipActive, actor(c), "unfrozen")
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+		}
+	}
+
+	plan, err := app.membershipPlan(c, membership)
+	if err != nil {
+		return err
+	}
+
+	freezes, err := app.store.GetFreezesByMembershipID(c.Request().Context(), membership.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting freezes", err, map[string]interface{}{
+			"membership_id": membership.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get freezes",
+		}
+	}
+
+	var freeze *model.Freeze
+	for _, f := range freezes {
+		if f.Open() {
+			freeze = f
+		}
+	}
+	if freeze == nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Membership has no freeze in progress",
+		}
+	}
+
+	now := time.Now()
+	credit := plan.FreezeCredit(model.FreezeDays(freeze.StartDate, now), model.FrozenDaysInYear(freezes, freeze.StartDate.Year()))
+
+	freeze.EndDate = &now
+	freeze.Days = credit
+	membership.DueDate = membership.DueDate.AddDate(0, 0, credit)
+
+	if err := app.store.UnfreezeMembership(c.Request().Context(), membership, freeze, transition); err != nil {
+		app.logger.WriteError("Error unfreezing membership", err, map[string]interface{}{
+			"membership_id": membership.ID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrMembershipStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership status changed, try again",
+			}
+		case errors.Is(err, postgres.ErrMembershipAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Member already has an active membership of this sport and type",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to unfreeze membership",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, freezeMembershipResponse{
+		Membership: newMembershipResponse(membership),
+		Freeze:     newFreezeResponse(freeze),
+	})
+}
+
+func (app *application) getMembershipFreezes(c echo.Context) error {
+	membership, err := app.membership(c)
+	if err != nil {
+		return err
+	}
+
+	freezes, err := app.store.GetFreezesByMembershipID(c.Request().Context(), membership.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting freezes", err, map[string]interface{}{
+			"membership_id": membership.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get freezes",
+		}
+	}
+
+	freezesResponse := make([]getFreezeResponse, len(freezes))
+	for i, freeze := range freezes {
+		freezesResponse[i] = newFreezeResponse(freeze)
+	}
+
+	return c.JSON(http.StatusOK, freezesResponse)
+}
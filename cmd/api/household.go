@@ -0,0 +1,465 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerHouseholdRoutes(e *echo.Group) {
+	e.POST("/households", app.addHousehold)
+	e.GET("/households/:id", app.getHouseholdByID)
+	e.DELETE("/households/:id", app.deleteHousehold)
+	e.POST("/households/:id/members", app.addHouseholdMember)
+	e.DELETE("/households/:id/members/:member_id", app.removeHouseholdMember)
+	e.POST("/households/:id/primary", app.setHouseholdPrimary)
+	e.GET("/households/:id/memberships", app.getHouseholdMemberships)
+	e.GET("/households/:id/balance", app.getHouseholdBalance)
+	e.POST("/households/:id/invoices", app.addHouseholdInvoice)
+	e.GET("/members/:id/household", app.getMemberHousehold)
+}
+
+// addHouseholdRequest creates a household whose first member, given by MemberID,
+// is its primary payer.
+type addHouseholdRequest struct {
+	Name         string             `json:"name"`
+	MemberID     uuid.UUID          `json:"member_id"`
+	Relationship model.Relationship `json:"relationship"`
+}
+
+type householdMemberRequest struct {
+	MemberID     uuid.UUID          `json:"member_id"`
+	Relationship model.Relationship `json:"relationship"`
+}
+
+type householdMemberResponse struct {
+	MemberID     uuid.UUID          `json:"member_id"`
+	Name         string             `json:"name"`
+	Relationship model.Relationship `json:"relationship"`
+	Primary      bool               `json:"primary"`
+}
+
+type getHouseholdResponse struct {
+	ID        uuid.UUID                 `json:"id"`
+	Name      string                    `json:"name"`
+	CreatedAt time.Time                 `json:"created_at"`
+	Members   []householdMemberResponse `json:"members"`
+}
+
+func newHouseholdResponse(household *model.Household) getHouseholdResponse {
+	resp := getHouseholdResponse{
+		ID:        household.ID,
+		Name:      household.Name,
+		CreatedAt: household.CreatedAt,
+		Members:   make([]householdMemberResponse, len(household.Members)),
+	}
+	for i, member := range household.Members {
+		resp.Members[i] = householdMemberResponse{
+			MemberID:     member.MemberID,
+			Name:         member.Name,
+			Relationship: member.Relationship,
+			Primary:      member.Primary,
+		}
+	}
+	return resp
+}
+
+func (app *application) addHousehold(c echo.Context) error {
+	var req addHouseholdRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	payer := &model.HouseholdMember{
+		MemberID:     req.MemberID,
+		Relationship: req.Relationship,
+		Primary:      true,
+	}
+	household := &model.Household{
+		Name:    req.Name,
+		Members: []*model.HouseholdMember{payer},
+	}
+
+	if err := household.Validate(); err != nil {
+		return app.validationError(err)
+	}
+	if err := payer.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddHousehold(c.Request().Context(), household); err != nil {
+		app.logger.WriteError("Error adding household", err, map[string]interface{}{
+			"member_id": req.MemberID,
+		})
+		return app.householdMemberError(err)
+	}
+
+	return c.JSON(http.StatusCreated, newHouseholdResponse(household))
+}
+
+func (app *application) getHouseholdByID(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newHouseholdResponse(household))
+}
+
+func (app *application) getMemberHousehold(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	household, err := app.store.GetHouseholdByMemberID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting household", err, map[string]interface{}{
+			"member_id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrHouseholdNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member does not belong to a household",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get household",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newHouseholdResponse(household))
+}
+
+func (app *application) deleteHousehold(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid household ID",
+		}
+	}
+
+	if err := app.store.DeleteHousehold(c.Request().Context(), id); err != nil {
+		app.logger.WriteError("Error deleting household", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrHouseholdNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Household not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete household",
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (app *application) addHouseholdMember(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	var req householdMemberRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	member := &model.HouseholdMember{
+		HouseholdID:  household.ID,
+		MemberID:     req.MemberID,
+		Relationship: req.Relationship,
+	}
+	if err := member.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddHouseholdMember(c.Request().Context(), member); err != nil {
+		app.logger.WriteError("Error adding household member", err, map[string]interface{}{
+			"id":        household.ID,
+			"member_id": req.MemberID,
+		})
+		return app.householdMemberError(err)
+	}
+	household.Members = append(household.Members, member)
+
+	return c.JSON(http.StatusCreated, newHouseholdResponse(household))
+}
+
+// householdMemberError maps the errors of adding a member to a household to a response.
+func (app *application) householdMemberError(err error) error {
+	switch {
+	case errors.Is(err, postgres.ErrHouseholdMemberExists):
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Member already belongs to a household",
+		}
+	case errors.Is(err, postgres.ErrHouseholdReferenceNotFound):
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Member not found",
+		}
+	case errors.Is(err, postgres.ErrMissingRequiredField):
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Missing required fields",
+		}
+	default:
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to add household member",
+		}
+	}
+}
+
+func (app *application) removeHouseholdMember(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	memberID, err := uuid.Parse(c.Param("member_id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	if member := household.Member(memberID); member != nil && member.Primary {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Primary payer cannot be removed, make another member primary first",
+		}
+	}
+
+	if err := app.store.RemoveHouseholdMember(c.Request().Context(), household.ID, memberID); err != nil {
+		app.logger.WriteError("Error removing household member", err, map[string]interface{}{
+			"id":        household.ID,
+			"member_id": memberID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrHouseholdMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Household member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to remove household member",
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+type setHouseholdPrimaryRequest struct {
+	MemberID uuid.UUID `json:"member_id"`
+}
+
+func (app *application) setHouseholdPrimary(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	var req setHouseholdPrimaryRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	if household.Member(req.MemberID) == nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Member does not belong to the household",
+		}
+	}
+
+	if err := app.store.SetHouseholdPrimary(c.Request().Context(), household.ID, req.MemberID); err != nil {
+		app.logger.WriteError("Error setting household primary", err, map[string]interface{}{
+			"id":        household.ID,
+			"member_id": req.MemberID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrHouseholdMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Member no longer belongs to the household",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to set household primary",
+			}
+		}
+	}
+
+	for _, member := range household.Members {
+		member.Primary = member.MemberID == req.MemberID
+	}
+
+	return c.JSON(http.StatusOK, newHouseholdResponse(household))
+}
+
+func (app *application) getHouseholdMemberships(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	memberships, err := app.store.GetHouseholdMemberships(c.Request().Context(), household.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting household memberships", err, map[string]interface{}{
+			"id": household.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get memberships",
+		}
+	}
+
+	membershipsResponse := make([]getMembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		membershipsResponse[i] = newMembershipResponse(membership)
+	}
+
+	return c.JSON(http.StatusOK, membershipsResponse)
+}
+
+// getHouseholdBalance returns the outstanding dues across the household, in the
+// same shape as the dues report.
+func (app *application) getHouseholdBalance(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	dues, err := app.store.GetHouseholdDues(c.Request().Context(), household.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting household dues", err, map[string]interface{}{
+			"id": household.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get household balance",
+		}
+	}
+
+	return c.JSON(http.StatusOK, newDuesReport(dues, time.Now()))
+}
+
+type addHouseholdInvoiceRequest struct {
+	MembershipIDs []uuid.UUID `json:"membership_ids"`
+}
+
+// addHouseholdInvoice bills the memberships of the whole household to its primary payer.
+func (app *application) addHouseholdInvoice(c echo.Context) error {
+	household, err := app.household(c)
+	if err != nil {
+		return err
+	}
+
+	var req addHouseholdInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	payer := household.Payer()
+	if payer == nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Household has no primary payer",
+		}
+	}
+
+	memberships, err := app.store.GetHouseholdMemberships(c.Request().Context(), household.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting household memberships", err, map[string]interface{}{
+			"id": household.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get memberships",
+		}
+	}
+
+	items, err := app.invoiceItems(c, memberships, req.MembershipIDs, household)
+	if err != nil {
+		return err
+	}
+
+	return app.createInvoice(c, payer.MemberID, items)
+}
+
+// household loads the household addressed by the :id path parameter.
+func (app *application) household(c echo.Context) (*model.Household, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid household ID",
+		}
+	}
+
+	household, err := app.store.GetHouseholdByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting household", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrHouseholdNotFound):
+			return nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Household not found",
+			}
+		default:
+			return nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get household",
+			}
+		}
+	}
+
+	return household, nil
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerInvoiceRoutes(e *echo.Group) {
+	e.POST("/invoices", app.addInvoice)
+	e.GET("/invoices/:id", app.getInvoiceByID)
+	e.GET("/members/:id/invoices", app.getMemberInvoices)
+	e.POST("/invoices/:id/issue", app.issueInvoice)
+	e.POST("/invoices/:id/pay", app.payInvoice)
+	e.POST("/invoices/:id/void", app.voidInvoice)
+}
+
+type addInvoiceRequest struct {
+	MemberID      uuid.UUID   `json:"member_id"`
+	MembershipIDs []uuid.UUID `json:"membership_ids"`
+}
+
+type getInvoiceItemResponse struct {
+	ID           uuid.UUID   `json:"id"`
+	MembershipID *uuid.UUID  `json:"membership_id"`
+	Description  string      `json:"description"`
+	Amount       model.Money `json:"amount"`
+}
+
+type getInvoiceResponse struct {
+	ID        uuid.UUID                `json:"id"`
+	Number    string                   `json:"number,omitempty"`
+	MemberID  uuid.UUID                `json:"member_id"`
+	Status    model.InvoiceStatus      `json:"status"`
+	Total     model.Money              `json:"total"`
+	CreatedAt time.Time                `json:"created_at"`
+	IssuedAt  *time.Time               `json:"issued_at,omitempty"`
+	Items     []getInvoiceItemResponse `json:"items,omitempty"`
+}
+
+func newInvoiceResponse(invoice *model.Invoice) getInvoiceResponse {
+	resp := getInvoiceResponse{
+		ID:        invoice.ID,
+		Number:    invoice.Number,
+		MemberID:  invoice.MemberID,
+		Status:    invoice.Status,
+		Total:     invoice.Total,
+		CreatedAt: invoice.CreatedAt,
+		IssuedAt:  invoice.IssuedAt,
+	}
+	for _, item := range invoice.Items {
+		resp.Items = append(resp.Items, getInvoiceItemResponse{
+			ID:           item.ID,
+			MembershipID: item.MembershipID,
+			Description:  item.Description,
+			Amount:       item.Amount,
+		})
+	}
+	return resp
+}
+
+func (app *application) addInvoice(c echo.Context) error {
+	var req addInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	if req.MemberID == uuid.Nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Required fields missing",
+		}
+	}
+
+	memberships, err := app.store.GetMembershipsByMemberID(c.Request().Context(), req.MemberID)
+	if err != nil {
+		app.logger.WriteError("Error getting memberships", err, map[string]interface{}{
+			"member_id": req.MemberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get memberships",
+		}
+	}
+
+	// Members of a household are billed to its primary payer.
+	household, err := app.store.GetHouseholdByMemberID(c.Request().Context(), req.MemberID)
+	if err != nil && !errors.Is(err, postgres.ErrHouseholdNotFound) {
+		app.logger.WriteError("Error getting household", err, map[string]interface{}{
+			"member_id": req.MemberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get household",
+		}
+	}
+	payerID := req.MemberID
+	if payer := household.Payer(); payer != nil && payer.MemberID != req.MemberID {
+		payerID = payer.MemberID
+	} else {
+		household = nil
+	}
+
+	items, err := app.invoiceItems(c, memberships, req.MembershipIDs, household)
+	if err != nil {
+		return err
+	}
+
+	return app.createInvoice(c, payerID, items)
+}
+
+// invoiceItems returns a line item for each of the memberships with the given IDs.
+// Without IDs, the active and unpaid memberships are invoiced. If household is not
+// nil, each description is prefixed with the name of the member it is for.
+func (app *application) invoiceItems(c echo.Context, memberships []*model.Membership, ids []uuid.UUID, household *model.Household) ([]*model.InvoiceItem, error) {
+	selected := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+	all := len(selected) == 0
+
+	var items []*model.InvoiceItem
+	for _, membership := range memberships {
+		if all && membership.Status != model.MembershipActive && membership.Status != model.MembershipPendingPayment || !all && !selected[membership.ID] {
+			continue
+		}
+		delete(selected, membership.ID)
+
+		description, err := app.invoiceItemDescription(c, membership)
+		if err != nil {
+			return nil, err
+		}
+		if member := household.Member(membership.MemberID); member != nil {
+			description = member.Name + ": " + description
+		}
+		membershipID := membership.ID
+		items = append(items, &model.InvoiceItem{
+			MembershipID: &membershipID,
+			Description:  description,
+			Amount:       membership.Fee,
+		})
+	}
+
+	if len(selected) > 0 {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Membership not found for member",
+		}
+	}
+	return items, nil
+}
+
+// createInvoice adds a draft invoice of the items, billed to the member.
+func (app *application) createInvoice(c echo.Context, memberID uuid.UUID, items []*model.InvoiceItem) error {
+	invoice, err := model.NewInvoice(memberID, items)
+	if err != nil {
+		app.logger.WriteError("Invalid invoice", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+		switch {
+		case errors.Is(err, model.ErrInvoiceEmpty):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "No memberships to invoice",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Memberships have different currencies",
+			}
+		}
+	}
+
+	if err := app.store.AddInvoice(c.Request().Context(), invoice); err != nil {
+		app.logger.WriteError("Error adding invoice", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to add invoice",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newInvoiceResponse(invoice))
+}
+
+func (app *application) invoiceItemDescription(c echo.Context, membership *model.Membership) (string, error) {
+	sport, err := app.store.GetSportByID(c.Request().Context(), membership.SportID)
+	if err != nil {
+		app.logger.WriteError("Error getting sport", err, map[string]interface{}{
+			"id": membership.SportID,
+		})
+		return "", &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get sport",
+		}
+	}
+
+	return fmt.Sprintf("%s %s, %s to %s",
+		sport.Name,
+		membership.Type,
+		membership.StartDate.Format("02 Jan 2006"),
+		membership.DueDate.Format("02 Jan 2006"),
+	), nil
+}
+
+// getInvoiceByID returns the invoice as JSON, or as a PDF when the client accepts application/pdf.
+func (app *application) getInvoiceByID(c echo.Context) error {
+	invoice, err := app.invoice(c)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "application/pdf") {
+		return c.JSON(http.StatusOK, newInvoiceResponse(invoice))
+	}
+
+	member, err := app.store.GetMemberByID(c.Request().Context(), invoice.MemberID)
+	if err != nil {
+		app.logger.WriteError("Error getting member", err, map[string]interface{}{
+			"id": invoice.MemberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get member",
+		}
+	}
+
+	pdf, err := app.renderInvoicePDF(invoice, member)
+	if err != nil {
+		app.logger.WriteError("Error rendering invoice", err, map[string]interface{}{
+			"id": invoice.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to render invoice",
+		}
+	}
+
+	name := invoice.Number
+	if name == "" {
+		name = "draft-" + invoice.ID.String()
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", name+".pdf"))
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+func (app *application) getMemberInvoices(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	invoices, err := app.store.GetInvoicesByMemberID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting invoices", err, map[string]interface{}{
+			"member_id": id,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get invoices",
+		}
+	}
+
+	invoicesResponse := make([]getInvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		invoicesResponse[i] = newInvoiceResponse(invoice)
+	}
+
+	return c.JSON(http.StatusOK, invoicesResponse)
+}
+
+func (app *application) issueInvoice(c echo.Context) error {
+	invoice, err := app.invoice(c)
+	if err != nil {
+		return err
+	}
+
+	if !invoice.CanTransition(model.InvoiceStatusIssued) {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Invoice cannot be issued",
+		}
+	}
+
+	if err := app.store.IssueInvoice(c.Request().Context(), invoice, app.club.invoicePrefix, time.Now()); err != nil {
+		app.logger.WriteError("Error issuing invoice", err, map[string]interface{}{
+			"id": invoice.ID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrInvoiceNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Invoice not found",
+			}
+		case errors.Is(err, postgres.ErrInvoiceStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Invoice cannot be issued",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to issue invoice",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newInvoiceResponse(invoice))
+}
+
+func (app *application) payInvoice(c echo.Context) error {
+	return app.updateInvoiceStatus(c, model.InvoiceStatusPaid)
+}
+
+func (app *application) voidInvoice(c echo.Context) error {
+	return app.updateInvoiceStatus(c, model.InvoiceStatusVoid)
+}
+
+func (app *application) updateInvoiceStatus(c echo.Context, status model.InvoiceStatus) error {
+	invoice, err := app.invoice(c)
+	if err != nil {
+		return err
+	}
+
+	if !invoice.CanTransition(status) {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("Invoice cannot move from %s to %s", invoice.Status, status),
+		}
+	}
+
+	if err := app.store.UpdateInvoiceStatus(c.Request().Context(), invoice, status); err != nil {
+		app.logger.WriteError("Error updating invoice status", err, map[string]interface{}{
+			"id":     invoice.ID,
+			"status": status,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrInvoiceStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Invoice status changed, try again",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update invoice",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newInvoiceResponse(invoice))
+}
+
+// invoice loads the invoice addressed by the :id path parameter.
+func (app *application) invoice(c echo.Context) (*model.Invoice, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid invoice ID",
+		}
+	}
+
+	invoice, err := app.store.GetInvoiceByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting invoice", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrInvoiceNotFound):
+			return nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Invoice not found",
+			}
+		default:
+			return nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get invoice",
+			}
+		}
+	}
+
+	return invoice, nil
+}
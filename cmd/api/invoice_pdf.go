@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/pdf"
+)
+
+const (
+	invoiceMarginLeft  = 50.0
+	invoiceMarginRight = pdf.PageWidth - 50.0
+	invoicePageBottom  = pdf.PageHeight - 80.0
+)
+
+func (app *application) renderInvoicePDF(invoice *model.Invoice, member *model.Member) ([]byte, error) {
+	doc := pdf.New()
+
+	doc.Text(invoiceMarginLeft, 70, 20, pdf.Bold, app.club.name)
+	doc.TextRight(invoiceMarginRight, 70, 20, pdf.Bold, "INVOICE")
+
+	number := invoice.Number
+	if number == "" {
+		number = "DRAFT"
+	}
+	date := invoice.CreatedAt
+	if invoice.IssuedAt != nil {
+		date = *invoice.IssuedAt
+	}
+	doc.TextRight(invoiceMarginRight, 95, 10, pdf.Regular, "Invoice no: "+number)
+	doc.TextRight(invoiceMarginRight, 110, 10, pdf.Regular, "Date: "+date.Format("02 Jan 2006"))
+	doc.TextRight(invoiceMarginRight, 125, 10, pdf.Regular, "Status: "+string(invoice.Status))
+
+	doc.Text(invoiceMarginLeft, 150, 10, pdf.Bold, "Bill to")
+	doc.Text(invoiceMarginLeft, 165, 10, pdf.Regular, member.Name)
+	doc.Text(invoiceMarginLeft, 180, 10, pdf.Regular, member.Email)
+	doc.Text(invoiceMarginLeft, 195, 10, pdf.Regular, member.PhoneNumber)
+	if member.Address != "" {
+		doc.Text(invoiceMarginLeft, 210, 10, pdf.Regular, member.Address)
+	}
+
+	y := 250.0
+	header := func() {
+		doc.Text(invoiceMarginLeft, y, 10, pdf.Bold, "Description")
+		doc.TextRight(invoiceMarginRight, y, 10, pdf.Bold, "Amount")
+		doc.Line(invoiceMarginLeft, y+6, invoiceMarginRight, y+6)
+		y += 24
+	}
+
+	header()
+	for _, item := range invoice.Items {
+		if y > invoicePageBottom {
+			doc.AddPage()
+			y = 70
+			header()
+		}
+		doc.Text(invoiceMarginLeft, y, 10, pdf.Regular, item.Description)
+		doc.TextRight(invoiceMarginRight, y, 10, pdf.Regular, item.Amount.String())
+		y += 18
+	}
+
+	doc.Line(invoiceMarginLeft, y-8, invoiceMarginRight, y-8)
+	doc.Text(invoiceMarginLeft, y+8, 11, pdf.Bold, "Total")
+	doc.TextRight(invoiceMarginRight, y+8, 11, pdf.Bold, invoice.Total.String())
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/labstack/echo/v4"
+)
+
+// listOptions parses the limit, cursor, sort and order query parameters of a
+// listing. A cursor carries the sort and order of the page it continues, so they
+// take precedence over the parameters.
+func listOptions(c echo.Context) (model.ListOptions, error) {
+	opts := model.ListOptions{
+		Sort:  model.SortField(c.QueryParam("sort")),
+		Order: model.SortOrder(c.QueryParam("order")),
+		Limit: model.DefaultListLimit,
+	}
+	if opts.Sort == "" {
+		opts.Sort = model.SortName
+	}
+	if opts.Order == "" {
+		opts.Order = model.SortAsc
+	}
+
+	if param := c.QueryParam("limit"); param != "" {
+		limit, err := strconv.Atoi(param)
+		if err != nil || limit < 1 || limit > model.MaxListLimit {
+			return opts, &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid limit, expected 1 to %d", model.MaxListLimit),
+			}
+		}
+		opts.Limit = limit
+	}
+
+	if param := c.QueryParam("cursor"); param != "" {
+		cursor, err := model.ParseCursor(param)
+		if err != nil {
+			return opts, &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid cursor",
+			}
+		}
+		opts.After = cursor
+		opts.Sort, opts.Order = cursor.Sort, cursor.Order
+	}
+
+	if opts.Sort != model.SortName && opts.Sort != model.SortCreated {
+		return opts, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid sort, expected name or created",
+		}
+	}
+	if opts.Order != model.SortAsc && opts.Order != model.SortDesc {
+		return opts, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid order, expected asc or desc",
+		}
+	}
+
+	return opts, nil
+}
@@ -2,24 +2,60 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Ruthvik10/membership-managment-system/internal/blob"
+	"github.com/Ruthvik10/membership-managment-system/internal/crypt"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
 	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
 	"github.com/Ruthvik10/membership-managment-system/internal/log"
+	"github.com/Ruthvik10/membership-managment-system/internal/mail"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 )
 
 type application struct {
 	store  store
+	blobs  blob.Store
+	mailer mail.Mailer
 	db     struct{ dbURL string }
 	logger logger
 	server struct {
 		addr string
 	}
+	worker struct {
+		interval    time.Duration
+		gracePeriod time.Duration
+	}
+	club struct {
+		name          string
+		invoicePrefix string
+	}
+	admin struct {
+		token string
+	}
+	members struct {
+		retentionPeriod time.Duration
+		phoneRegion     string
+	}
+	sensitive struct {
+		token string
+	}
+	photos struct {
+		maxSize int64
+	}
+	email struct {
+		signer    *crypt.Signer
+		changeTTL time.Duration
+		publicURL string
+	}
 }
 
 func (app *application) registerRoutes() *echo.Echo {
@@ -29,15 +65,24 @@ func (app *application) registerRoutes() *echo.Echo {
 		app.registerHealthCheckRoutes(v1)
 		app.registerMemberRoutes(v1)
 		app.registerSportRoutes(v1)
+		app.registerMembershipRoutes(v1)
+		app.registerPaymentRoutes(v1)
+		app.registerPlanRoutes(v1)
+		app.registerInvoiceRoutes(v1)
+		app.registerReportRoutes(v1)
+		app.registerFreezeRoutes(v1)
+		app.registerHouseholdRoutes(v1)
+		app.registerSensitiveRoutes(v1)
+		app.registerCustomFieldRoutes(v1)
+		app.registerSegmentRoutes(v1)
+		app.registerBatchRoutes(v1)
+		app.registerAdminRoutes(v1)
 	}
 
 	return e
 }
 
-func (app *application) runServer(e *echo.Echo) {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
-
+func (app *application) runServer(ctx context.Context, e *echo.Echo) {
 	go func() {
 		if err := e.Start(app.server.addr); err != nil && err != http.ErrServerClosed {
 			app.logger.WriteError("shutting down the server", err, nil)
@@ -77,6 +122,84 @@ func main() {
 
 	app.db.dbURL = cfg.DBURL
 	app.server.addr = cfg.APIAddr
+	app.worker.interval = cfg.MembershipWorkerInterval
+	app.worker.gracePeriod = cfg.MembershipGracePeriod
+	app.club.name = cfg.ClubName
+	app.club.invoicePrefix = cfg.InvoicePrefix
+	app.admin.token = cfg.AdminToken
+	app.members.retentionPeriod = cfg.MemberRetentionPeriod
+	app.members.phoneRegion = strings.ToUpper(cfg.PhoneDefaultRegion)
+	app.sensitive.token = cfg.SensitiveDataToken
+	app.photos.maxSize = cfg.PhotoMaxSize
+	app.email.changeTTL = cfg.EmailChangeTTL
+	app.email.publicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+	if app.worker.interval <= 0 || app.worker.gracePeriod < 0 {
+		app.logger.WriteFatal("Invalid membership worker configuration", nil, map[string]interface{}{
+			"interval":     cfg.MembershipWorkerInterval.String(),
+			"grace_period": cfg.MembershipGracePeriod.String(),
+		})
+	}
+	if !model.ValidPhoneRegion(app.members.phoneRegion) {
+		app.logger.WriteFatal("Invalid phone default region", nil, map[string]interface{}{
+			"phone_default_region": cfg.PhoneDefaultRegion,
+		})
+	}
+	if app.photos.maxSize <= 0 {
+		app.logger.WriteFatal("Invalid photo max size", nil, map[string]interface{}{
+			"photo_max_size": cfg.PhotoMaxSize,
+		})
+	}
+	if app.email.changeTTL <= 0 {
+		app.logger.WriteFatal("Invalid email change TTL", nil, map[string]interface{}{
+			"email_change_ttl": cfg.EmailChangeTTL.String(),
+		})
+	}
+	if app.members.retentionPeriod <= 0 {
+		app.logger.WriteFatal("Invalid member retention period", nil, map[string]interface{}{
+			"retention_period": cfg.MemberRetentionPeriod.String(),
+		})
+	}
+
+	var cipher *crypt.Cipher
+	if cfg.EncryptionKey != "" || app.sensitive.token != "" {
+		key, err := crypt.ParseKey(cfg.EncryptionKey)
+		if err != nil {
+			app.logger.WriteFatal("Invalid encryption key", err, nil)
+		}
+		if cipher, err = crypt.NewCipher(key); err != nil {
+			app.logger.WriteFatal("Invalid encryption key", err, nil)
+		}
+	}
+
+	blobs, err := blob.NewLocal(cfg.BlobDir)
+	if err != nil {
+		app.logger.WriteFatal("Error opening blob storage", err, map[string]interface{}{
+			"dir": cfg.BlobDir,
+		})
+	}
+	app.blobs = blobs
+
+	secret := []byte(cfg.EmailTokenSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			app.logger.WriteFatal("Error generating email token secret", err, nil)
+		}
+		app.logger.WriteInfo("No EMAIL_TOKEN_SECRET set, email confirmation links will not survive a restart", nil)
+	}
+	app.email.signer = crypt.NewSigner(secret)
+
+	switch cfg.Mailer {
+	case "outbox":
+		app.mailer, err = mail.NewOutbox(cfg.MailOutboxDir, cfg.MailFrom)
+	case "smtp":
+		app.mailer, err = mail.NewSMTP(cfg.SMTPAddr, cfg.MailFrom, cfg.SMTPUsername, cfg.SMTPPassword)
+	default:
+		err = fmt.Errorf("unknown mailer %q, expected outbox or smtp", cfg.Mailer)
+	}
+	if err != nil {
+		app.logger.WriteFatal("Error setting up the mailer", err, nil)
+	}
 
 	conn, err := app.openDB()
 	if err != nil {
@@ -87,16 +210,57 @@ func main() {
 
 	memberStore := postgres.NewMemberStore(conn)
 	sportStore := postgres.NewSportStore(conn)
+	membershipStore := postgres.NewMembershipStore(conn)
+	paymentStore := postgres.NewPaymentStore(conn)
+	planStore := postgres.NewPlanStore(conn)
+	invoiceStore := postgres.NewInvoiceStore(conn)
+	freezeStore := postgres.NewFreezeStore(conn)
+	householdStore := postgres.NewHouseholdStore(conn)
+	sensitiveStore := postgres.NewSensitiveStore(conn, cipher)
+	customFieldStore := postgres.NewCustomFieldStore(conn)
+	segmentStore := postgres.NewSegmentStore(conn)
+	batchStore := postgres.NewBatchStore(conn)
 
 	storeRegistry := struct {
 		*postgres.MemberStore
 		*postgres.SportStore
+		*postgres.MembershipStore
+		*postgres.PaymentStore
+		*postgres.PlanStore
+		*postgres.InvoiceStore
+		*postgres.FreezeStore
+		*postgres.HouseholdStore
+		*postgres.SensitiveStore
+		*postgres.CustomFieldStore
+		*postgres.SegmentStore
+		*postgres.BatchStore
 	}{
 		memberStore,
 		sportStore,
+		membershipStore,
+		paymentStore,
+		planStore,
+		invoiceStore,
+		freezeStore,
+		householdStore,
+		sensitiveStore,
+		customFieldStore,
+		segmentStore,
+		batchStore,
 	}
 	app.store = storeRegistry
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		app.runMembershipWorker(ctx)
+	}()
+
 	e := app.registerRoutes()
-	app.runServer(e)
+	app.runServer(ctx, e)
+	wg.Wait()
 }
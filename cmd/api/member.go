@@ -2,7 +2,12 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
@@ -15,9 +20,26 @@ func (app *application) registerMemberRoutes(e *echo.Group) {
 	e.POST("/members", app.addMember)
 	e.GET("/members/:id", app.getMemberByID)
 	e.GET("/members/email/:email", app.getMemberByEmail)
+	e.GET("/members/email/confirm", app.confirmEmailChange)
+	e.POST("/members/email/confirm", app.confirmEmailChange)
+	e.GET("/members/phone/:phone", app.getMemberByPhone)
+	e.GET("/members/search", app.searchMembers)
+	e.GET("/members/anniversaries", app.getMemberAnniversaries)
+	e.GET("/members/duplicates", app.getDuplicateMembers)
+	e.POST("/members/merge", app.mergeMembers)
+	e.GET("/members/:id/merges", app.getMemberMerges)
 	e.GET("/members", app.getAllMembers)
 	e.PATCH("/members/:id", app.updateMember)
 	e.DELETE("/members/:id", app.deleteMember)
+	e.POST("/members/:id/restore", app.restoreMember)
+	e.GET("/members/:id/status-history", app.getMemberStatusHistory)
+	e.GET("/members/:id/tags", app.getMemberTags)
+	e.POST("/members/:id/tags", app.addMemberTags)
+	e.DELETE("/members/:id/tags", app.removeMemberTags)
+	e.GET("/members/:id/credits", app.getMemberCredits)
+	e.PUT("/members/:id/photo", app.putMemberPhoto)
+	e.GET("/members/:id/photo", app.getMemberPhoto)
+	e.DELETE("/members/:id/photo", app.deleteMemberPhoto)
 }
 
 type addMemberRequest struct {
@@ -26,16 +48,8 @@ type addMemberRequest struct {
 	PhoneNumber string    `json:"phone_number"`
 	Address     string    `json:"address"`
 	JoinDate    time.Time `json:"join_date"`
-}
-
-type addMemberResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	PhoneNumber string    `json:"phone_number"`
-	Address     string    `json:"address"`
-	JoinDate    time.Time `json:"join_date"`
-	Status      string    `json:"status"`
+	// CustomFields holds the values of custom fields by name; null values are ignored.
+	CustomFields map[string]any `json:"custom_fields"`
 }
 
 func (app *application) addMember(c echo.Context) error {
@@ -48,31 +62,30 @@ func (app *application) addMember(c echo.Context) error {
 		}
 	}
 
-	if req.Email == "" || req.Name == "" || req.PhoneNumber == "" {
-		app.logger.WriteError("Required fields missing", nil, map[string]interface{}{
-			"email": req.Email,
-			"name":  req.Name,
-			"phone": req.PhoneNumber,
-		})
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Required fields missing",
+	member := &model.Member{
+		Name:         req.Name,
+		Email:        req.Email,
+		PhoneNumber:  model.NormalizePhone(req.PhoneNumber, app.members.phoneRegion),
+		Address:      req.Address,
+		JoinDate:     req.JoinDate,
+		Status:       model.MemberStatusActive,
+		CustomFields: map[string]any{},
+	}
+	if member.JoinDate.IsZero() {
+		member.JoinDate = time.Now().Truncate(24 * time.Hour)
+	}
+	for name, value := range req.CustomFields {
+		if value != nil {
+			member.CustomFields[name] = value
 		}
 	}
 
-	member := &model.Member{
-		Name:        req.Name,
-		Email:       req.Email,
-		PhoneNumber: req.PhoneNumber,
-		Address:     req.Address,
-		Status:      model.MemberStatusActive,
+	fields, err := app.customFields(c)
+	if err != nil {
+		return err
 	}
-
-	if !member.Valid() {
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid member details",
-		}
+	if err := member.Validate(fields); err != nil {
+		return app.validationError(err)
 	}
 
 	if err := app.store.AddMember(c.Request().Context(), member); err != nil {
@@ -101,26 +114,55 @@ func (app *application) addMember(c echo.Context) error {
 		}
 	}
 
-	memberResponse := addMemberResponse{
+	memberResponse := newMemberResponse(member)
+
+	return c.JSON(http.StatusCreated, memberResponse)
+}
+
+type tenureResponse struct {
+	Years  int `json:"years"`
+	Months int `json:"months"`
+}
+
+type getMemberResponse struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Email       string         `json:"email"`
+	PhoneNumber string         `json:"phone_number"`
+	Address     string         `json:"address"`
+	JoinDate    time.Time      `json:"join_date"`
+	Tenure      tenureResponse `json:"tenure"`
+	Status      string         `json:"status"`
+	DeletedAt   *time.Time     `json:"deleted_at,omitempty"`
+	PhotoURL    *string        `json:"photo_url"`
+	// CustomFields holds the values of the club's custom fields by name.
+	CustomFields map[string]any `json:"custom_fields"`
+	// PendingEmail is the new email awaiting confirmation after an update.
+	PendingEmail *string `json:"pending_email,omitempty"`
+}
+
+func newMemberResponse(member *model.Member) getMemberResponse {
+	tenure := member.Tenure(time.Now())
+	customFields := member.CustomFields
+	if customFields == nil {
+		customFields = map[string]any{}
+	}
+	return getMemberResponse{
 		ID:          member.ID,
 		Name:        member.Name,
 		Email:       member.Email,
 		PhoneNumber: member.PhoneNumber,
 		Address:     member.Address,
-		Status:      model.MemberStatusMap[member.Status],
+		JoinDate:    member.JoinDate,
+		Tenure: tenureResponse{
+			Years:  tenure.Years,
+			Months: tenure.Months,
+		},
+		Status:       model.MemberStatusMap[member.Status],
+		DeletedAt:    member.DeletedAt,
+		PhotoURL:     memberPhotoURL(member),
+		CustomFields: customFields,
 	}
-
-	return c.JSON(http.StatusCreated, memberResponse)
-}
-
-type getMemberResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	PhoneNumber string    `json:"phone_number"`
-	Address     string    `json:"address"`
-	JoinDate    time.Time `json:"join_date"`
-	Status      string    `json:"status"`
 }
 
 func (app *application) getMemberByID(c echo.Context) error {
@@ -152,14 +194,7 @@ func (app *application) getMemberByID(c echo.Context) error {
 		}
 	}
 
-	memberResponse := getMemberResponse{
-		ID:          member.ID,
-		Name:        member.Name,
-		Email:       member.Email,
-		PhoneNumber: member.PhoneNumber,
-		Address:     member.Address,
-		Status:      model.MemberStatusMap[member.Status],
-	}
+	memberResponse := newMemberResponse(member)
 
 	return c.JSON(http.StatusOK, memberResponse)
 }
@@ -187,41 +222,268 @@ func (app *application) getMemberByEmail(c echo.Context) error {
 		}
 	}
 
-	memberResponse := getMemberResponse{
-		ID:          member.ID,
-		Name:        member.Name,
-		Email:       member.Email,
-		PhoneNumber: member.PhoneNumber,
-		Address:     member.Address,
-		Status:      model.MemberStatusMap[member.Status],
-	}
+	memberResponse := newMemberResponse(member)
 
 	return c.JSON(http.StatusOK, memberResponse)
 }
 
+// getMemberByPhone finds the member however the number is typed, e.g. with
+// spaces, dashes or without the country code of the default region.
+func (app *application) getMemberByPhone(c echo.Context) error {
+	param, err := url.PathUnescape(c.Param("phone"))
+	if err != nil {
+		param = c.Param("phone")
+	}
+	phone := model.NormalizePhone(param, app.members.phoneRegion)
+	if !model.ValidPhone(phone) {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid phone number",
+		}
+	}
+
+	member, err := app.store.GetMemberByPhone(c.Request().Context(), phone)
+	if err != nil {
+		app.logger.WriteError("Error getting member", err, map[string]interface{}{
+			"phone": phone,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get member",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newMemberResponse(member))
+}
+
+// customFieldFilters adds to opts the custom field values selected by the
+// custom_fields.<name> query parameters.
+func (app *application) customFieldFilters(c echo.Context, opts *model.MemberListOptions) error {
+	var fields []*model.CustomField
+	for param, values := range c.QueryParams() {
+		name, ok := strings.CutPrefix(param, "custom_fields.")
+		if !ok {
+			continue
+		}
+
+		if fields == nil {
+			var err error
+			if fields, err = app.customFields(c); err != nil {
+				return err
+			}
+		}
+		i := slices.IndexFunc(fields, func(f *model.CustomField) bool { return f.Name == name })
+		if i < 0 {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Unknown custom field %q", name),
+			}
+		}
+		value, err := fields[i].ParseValue(values[0])
+		if err != nil {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid value for custom field %q", name),
+			}
+		}
+
+		if opts.CustomFields == nil {
+			opts.CustomFields = make(map[string]any)
+		}
+		opts.CustomFields[name] = value
+	}
+	return nil
+}
+
+type listMembersResponse struct {
+	Members    []getMemberResponse `json:"members"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
 func (app *application) getAllMembers(c echo.Context) error {
-	members, err := app.store.GetAllMembers(c.Request().Context())
+	listOpts, err := listOptions(c)
+	if err != nil {
+		return err
+	}
+	opts := model.MemberListOptions{ListOptions: listOpts}
+
+	if param := c.QueryParam("archived"); param != "" {
+		archived, err := strconv.ParseBool(param)
+		if err != nil {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid archived flag",
+			}
+		}
+		opts.Archived = archived
+	}
+
+	if param := c.QueryParam("status"); param != "" {
+		for status, name := range model.MemberStatusMap {
+			if strings.EqualFold(name, param) {
+				opts.Status = &status
+			}
+		}
+		if opts.Status == nil {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid status",
+			}
+		}
+	}
+
+	for _, tag := range c.QueryParams()["tag"] {
+		opts.Tags = append(opts.Tags, model.NormalizeTag(tag))
+	}
+
+	if err := app.customFieldFilters(c, &opts); err != nil {
+		return err
+	}
+
+	return app.listMembers(c, opts)
+}
+
+// listMembers responds with the page of members selected by opts.
+func (app *application) listMembers(c echo.Context, opts model.MemberListOptions) error {
+	members, next, err := app.store.ListMembers(c.Request().Context(), opts)
 	if err != nil {
 		app.logger.WriteError("Error getting members", err, nil)
+		switch {
+		case errors.Is(err, model.ErrInvalidCursor):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid cursor",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get members",
+			}
+		}
+	}
+
+	membersResponse := make([]getMemberResponse, len(members))
+	for i, member := range members {
+		membersResponse[i] = newMemberResponse(member)
+	}
+
+	resp := listMembersResponse{Members: membersResponse}
+	if next != nil {
+		resp.NextCursor = next.String()
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type memberAnniversaryResponse struct {
+	Member getMemberResponse `json:"member"`
+	Years  int               `json:"years"`
+}
+
+// getMemberAnniversaries lists the members whose joining anniversary falls in the
+// given month (the current month by default), with the years they complete.
+func (app *application) getMemberAnniversaries(c echo.Context) error {
+	now := time.Now()
+	month := now.Month()
+	if param := c.QueryParam("month"); param != "" {
+		m, err := strconv.Atoi(param)
+		if err != nil || m < 1 || m > 12 {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid month, expected 1 to 12",
+			}
+		}
+		month = time.Month(m)
+	}
+
+	members, err := app.store.GetMemberAnniversaries(c.Request().Context(), month, now.Year())
+	if err != nil {
+		app.logger.WriteError("Error getting member anniversaries", err, map[string]interface{}{
+			"month": month,
+		})
 		return &echo.HTTPError{
 			Code:    http.StatusInternalServerError,
-			Message: "Failed to get members",
+			Message: "Failed to get member anniversaries",
 		}
 	}
 
-	membersResponse := make([]getMemberResponse, len(members))
+	anniversariesResponse := make([]memberAnniversaryResponse, len(members))
 	for i, member := range members {
-		membersResponse[i] = getMemberResponse{
-			ID:          member.ID,
-			Name:        member.Name,
-			Email:       member.Email,
-			PhoneNumber: member.PhoneNumber,
-			Address:     member.Address,
-			Status:      model.MemberStatusMap[member.Status],
+		anniversariesResponse[i] = memberAnniversaryResponse{
+			Member: newMemberResponse(member),
+			Years:  now.Year() - member.JoinDate.Year(),
 		}
 	}
 
-	return c.JSON(http.StatusOK, membersResponse)
+	return c.JSON(http.StatusOK, anniversariesResponse)
+}
+
+// searchResultLimit is the number of search results returned unless a limit is given.
+const searchResultLimit = 20
+
+type searchMemberResponse struct {
+	Member       getMemberResponse `json:"member"`
+	MatchedField string            `json:"matched_field"`
+	Highlight    string            `json:"highlight"`
+	Score        float64           `json:"score"`
+}
+
+func (app *application) searchMembers(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if len([]rune(query)) < 2 {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Search query must be at least 2 characters",
+		}
+	}
+
+	limit := searchResultLimit
+	if param := c.QueryParam("limit"); param != "" {
+		l, err := strconv.Atoi(param)
+		if err != nil || l < 1 || l > model.MaxListLimit {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid limit, expected 1 to %d", model.MaxListLimit),
+			}
+		}
+		limit = l
+	}
+
+	// Phone numbers are stored in E.164 form, so search for a typed number in that form.
+	if phone := model.NormalizePhone(query, app.members.phoneRegion); model.ValidPhone(phone) {
+		query = phone
+	}
+
+	matches, err := app.store.SearchMembers(c.Request().Context(), query, limit)
+	if err != nil {
+		app.logger.WriteError("Error searching members", err, map[string]interface{}{
+			"q": query,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to search members",
+		}
+	}
+
+	searchResponse := make([]searchMemberResponse, len(matches))
+	for i, match := range matches {
+		searchResponse[i] = searchMemberResponse{
+			Member:       newMemberResponse(match.Member),
+			MatchedField: match.Field,
+			Highlight:    model.Highlight(match.Value(), query),
+			Score:        match.Score,
+		}
+	}
+
+	return c.JSON(http.StatusOK, searchResponse)
 }
 
 type updateMemberRequest struct {
@@ -231,8 +493,12 @@ type updateMemberRequest struct {
 	Address     *string             `json:"address"`
 	JoinDate    *time.Time          `json:"join_date"`
 	Status      *model.MemberStatus `json:"status"`
+	// StatusReason and StatusNote explain a change of Status; the reason is required with one.
+	StatusReason model.MemberStatusReason `json:"status_reason"`
+	StatusNote   string                   `json:"status_note"`
+	// CustomFields sets the values of the custom fields given; a null value clears it.
+	CustomFields map[string]any `json:"custom_fields"`
 }
-type updateMemberResponse = getMemberResponse
 
 func (app *application) updateMember(c echo.Context) error {
 	id, err := uuid.Parse(c.Param("id"))
@@ -275,27 +541,86 @@ func (app *application) updateMember(c echo.Context) error {
 	if req.Name != nil {
 		member.Name = *req.Name
 	}
+	// A new email address is held as pending until the member confirms it,
+	// only a change in its case is applied right away.
+	var newEmail string
 	if req.Email != nil {
-		member.Email = *req.Email
+		if strings.EqualFold(*req.Email, member.Email) {
+			member.Email = *req.Email
+		} else {
+			newEmail = *req.Email
+		}
 	}
 	if req.PhoneNumber != nil {
-		member.PhoneNumber = *req.PhoneNumber
+		member.PhoneNumber = model.NormalizePhone(*req.PhoneNumber, app.members.phoneRegion)
 	}
 	if req.Address != nil {
 		member.Address = *req.Address
 	}
-	if req.Status != nil {
+	if req.JoinDate != nil {
+		member.JoinDate = *req.JoinDate
+	}
+	// A change of status needs a reason and is recorded in the status history.
+	var statusChange *model.MemberStatusChange
+	if req.Status != nil && *req.Status != member.Status {
+		statusChange = &model.MemberStatusChange{
+			MemberID: member.ID,
+			From:     member.Status,
+			To:       *req.Status,
+			Reason:   req.StatusReason,
+			Note:     req.StatusNote,
+			Actor:    actor(c),
+		}
 		member.Status = *req.Status
 	}
+	if member.CustomFields == nil {
+		member.CustomFields = map[string]any{}
+	}
+	for name, value := range req.CustomFields {
+		if value == nil {
+			delete(member.CustomFields, name)
+		} else {
+			member.CustomFields[name] = value
+		}
+	}
 
-	if !member.Valid() {
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid member details",
+	fields, err := app.customFields(c)
+	if err != nil {
+		return err
+	}
+	validated := *member
+	if newEmail != "" {
+		validated.Email = newEmail
+	}
+	if err := validated.Validate(fields); err != nil {
+		return app.validationError(err)
+	}
+	if statusChange != nil {
+		if err := statusChange.Validate(); err != nil {
+			return app.validationError(err)
+		}
+	}
+
+	if newEmail != "" {
+		other, err := app.store.GetMemberByEmail(c.Request().Context(), newEmail)
+		switch {
+		case err == nil && other.ID != member.ID:
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Email is already in use",
+			}
+		case err != nil && !errors.Is(err, postgres.ErrMemberNotFound):
+			app.logger.WriteError("Error getting member", err, map[string]interface{}{
+				"email": newEmail,
+			})
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get member",
+			}
 		}
 	}
 
-	if err := app.store.UpdateMember(c.Request().Context(), member); err != nil {
+	if err := app.store.UpdateMember(c.Request().Context(), member, statusChange); err != nil {
 		app.logger.WriteError("Error updating member", err, map[string]interface{}{
 			"id": id,
 		})
@@ -311,6 +636,11 @@ func (app *application) updateMember(c echo.Context) error {
 				Code:    http.StatusNotFound,
 				Message: "Member not found",
 			}
+		case errors.Is(err, postgres.ErrMemberStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Member status changed, try again",
+			}
 		case errors.Is(err, postgres.ErrMissingRequiredField):
 			return &echo.HTTPError{
 				Code:    http.StatusBadRequest,
@@ -324,13 +654,14 @@ func (app *application) updateMember(c echo.Context) error {
 		}
 	}
 
-	memberResponse := updateMemberResponse{
-		ID:          member.ID,
-		Name:        member.Name,
-		Email:       member.Email,
-		PhoneNumber: member.PhoneNumber,
-		Address:     member.Address,
-		Status:      model.MemberStatusMap[member.Status],
+	memberResponse := newMemberResponse(member)
+
+	if newEmail != "" {
+		change, err := app.requestEmailChange(c, member, newEmail)
+		if err != nil {
+			return err
+		}
+		memberResponse.PendingEmail = &change.Email
 	}
 
 	return c.JSON(http.StatusOK, memberResponse)
@@ -366,3 +697,73 @@ func (app *application) deleteMember(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+func (app *application) restoreMember(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	member, err := app.store.RestoreMember(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error restoring member", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Archived member not found",
+			}
+		case errors.Is(err, postgres.ErrMemberAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Another member has the same email",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to restore member",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newMemberResponse(member))
+}
+
+// member loads the member addressed by the :id path parameter.
+func (app *application) member(c echo.Context) (*model.Member, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	member, err := app.store.GetMemberByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting member", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get member",
+			}
+		}
+	}
+
+	return member, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type memberCreditResponse struct {
+	ID           uuid.UUID   `json:"id"`
+	Amount       model.Money `json:"amount"`
+	MembershipID *uuid.UUID  `json:"membership_id,omitempty"`
+	InvoiceID    *uuid.UUID  `json:"invoice_id,omitempty"`
+	Reason       string      `json:"reason"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+type memberCreditsResponse struct {
+	Balances []model.Money          `json:"balances"`
+	Entries  []memberCreditResponse `json:"entries"`
+}
+
+// getMemberCredits returns the member's credit balance in each currency and the
+// ledger it is made of.
+func (app *application) getMemberCredits(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+
+	credits, err := app.store.GetMemberCredits(c.Request().Context(), member.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting member credits", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get member credits",
+		}
+	}
+
+	resp := memberCreditsResponse{
+		Balances: model.CreditBalances(credits),
+		Entries:  make([]memberCreditResponse, len(credits)),
+	}
+	if resp.Balances == nil {
+		resp.Balances = []model.Money{}
+	}
+	for i, credit := range credits {
+		resp.Entries[i] = memberCreditResponse{
+			ID:           credit.ID,
+			Amount:       credit.Amount,
+			MembershipID: credit.MembershipID,
+			InvoiceID:    credit.InvoiceID,
+			Reason:       credit.Reason,
+			CreatedAt:    credit.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/Ruthvik10/membership-managment-system/internal/mail"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// emailChangeClaims is the payload of the signed token confirming an email change.
+type emailChangeClaims struct {
+	MemberID  uuid.UUID `json:"member_id"`
+	Email     string    `json:"email"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+// requestEmailChange holds the new email of the member as pending and sends a
+// link confirming it to the new address.
+func (app *application) requestEmailChange(c echo.Context, member *model.Member, email string) (*model.EmailChange, error) {
+	change := &model.EmailChange{
+		MemberID:  member.ID,
+		Email:     email,
+		ExpiresAt: time.Now().Add(app.email.changeTTL).Truncate(time.Second),
+	}
+	if err := app.store.RequestEmailChange(c.Request().Context(), change); err != nil {
+		app.logger.WriteError("Error requesting email change", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return nil, &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to request email change",
+		}
+	}
+
+	claims, err := json.Marshal(emailChangeClaims{
+		MemberID:  change.MemberID,
+		Email:     change.Email,
+		ExpiresAt: change.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	link := fmt.Sprintf("%s/api/v1/members/email/confirm?token=%s", app.email.publicURL, url.QueryEscape(app.email.signer.Sign(claims)))
+
+	msg := mail.Message{
+		To:      change.Email,
+		Subject: fmt.Sprintf("Confirm your new email address for %s", app.club.name),
+		Body: fmt.Sprintf("Hello %s,\r\n\r\nPlease confirm this is your new email address by opening the link below before %s.\r\n\r\n%s\r\n\r\nIf you did not ask for this change, you can ignore this email.\r\n",
+			member.Name, change.ExpiresAt.Format(time.RFC1123), link),
+	}
+	if err := app.mailer.Send(c.Request().Context(), msg); err != nil {
+		app.logger.WriteError("Error sending email change confirmation", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return nil, &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to send the email confirmation",
+		}
+	}
+
+	return change, nil
+}
+
+// confirmEmailChange replaces the member's email with the pending one named by
+// the token sent to the new address.
+func (app *application) confirmEmailChange(c echo.Context) error {
+	payload, err := app.email.signer.Verify(c.FormValue("token"))
+	var claims emailChangeClaims
+	if err == nil {
+		err = json.Unmarshal(payload, &claims)
+	}
+	if err != nil {
+		app.logger.WriteError("Invalid email change token", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid token",
+		}
+	}
+
+	change := &model.EmailChange{
+		MemberID:  claims.MemberID,
+		Email:     claims.Email,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}
+	if change.Expired(time.Now()) {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Email change has expired, please request it again",
+		}
+	}
+
+	member, err := app.store.ConfirmEmailChange(c.Request().Context(), change)
+	if err != nil {
+		app.logger.WriteError("Error confirming email change", err, map[string]interface{}{
+			"id": change.MemberID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrEmailChangeNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Email change is no longer pending",
+			}
+		case errors.Is(err, postgres.ErrMemberAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Email is already in use",
+			}
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to confirm email change",
+			}
+		}
+	}
+
+	app.logger.WriteInfo("Confirmed email change", map[string]interface{}{
+		"id": member.ID,
+	})
+
+	return c.JSON(http.StatusOK, newMemberResponse(member))
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// duplicateResultLimit is the number of duplicate pairs returned unless a limit is given.
+const duplicateResultLimit = 50
+
+type duplicateMemberResponse struct {
+	Member         getMemberResponse       `json:"member"`
+	Duplicate      getMemberResponse       `json:"duplicate"`
+	Reasons        []model.DuplicateReason `json:"reasons"`
+	NameSimilarity float64                 `json:"name_similarity"`
+}
+
+// getDuplicateMembers lists pairs of members that are likely the same person.
+// similarity sets the name similarity, from 0 to 1, from which names match.
+func (app *application) getDuplicateMembers(c echo.Context) error {
+	similarity := model.DefaultDuplicateNameSimilarity
+	if param := c.QueryParam("similarity"); param != "" {
+		s, err := strconv.ParseFloat(param, 64)
+		if err != nil || s <= 0 || s > 1 {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid similarity, expected a number above 0 and up to 1",
+			}
+		}
+		similarity = s
+	}
+
+	limit := duplicateResultLimit
+	if param := c.QueryParam("limit"); param != "" {
+		l, err := strconv.Atoi(param)
+		if err != nil || l < 1 || l > model.MaxListLimit {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Invalid limit, expected 1 to %d", model.MaxListLimit),
+			}
+		}
+		limit = l
+	}
+
+	matches, err := app.store.FindDuplicateMembers(c.Request().Context(), similarity, limit)
+	if err != nil {
+		app.logger.WriteError("Error finding duplicate members", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to find duplicate members",
+		}
+	}
+
+	duplicatesResponse := make([]duplicateMemberResponse, len(matches))
+	for i, match := range matches {
+		duplicatesResponse[i] = duplicateMemberResponse{
+			Member:         newMemberResponse(match.Member),
+			Duplicate:      newMemberResponse(match.Duplicate),
+			Reasons:        match.Reasons,
+			NameSimilarity: match.NameSimilarity,
+		}
+	}
+
+	return c.JSON(http.StatusOK, duplicatesResponse)
+}
+
+type mergeMembersRequest struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+type memberMergeResponse struct {
+	ID             uuid.UUID   `json:"id"`
+	SurvivorID     uuid.UUID   `json:"survivor_id"`
+	DuplicateID    uuid.UUID   `json:"duplicate_id"`
+	DuplicateName  string      `json:"duplicate_name"`
+	DuplicateEmail string      `json:"duplicate_email"`
+	DuplicatePhone string      `json:"duplicate_phone"`
+	MembershipIDs  []uuid.UUID `json:"membership_ids"`
+	InvoiceIDs     []uuid.UUID `json:"invoice_ids"`
+	Actor          string      `json:"actor"`
+	MergedAt       time.Time   `json:"merged_at"`
+}
+
+func newMemberMergeResponse(merge *model.MemberMerge) memberMergeResponse {
+	return memberMergeResponse{
+		ID:             merge.ID,
+		SurvivorID:     merge.SurvivorID,
+		DuplicateID:    merge.DuplicateID,
+		DuplicateName:  merge.DuplicateName,
+		DuplicateEmail: merge.DuplicateEmail,
+		DuplicatePhone: merge.DuplicatePhone,
+		MembershipIDs:  merge.MembershipIDs,
+		InvoiceIDs:     merge.InvoiceIDs,
+		Actor:          merge.Actor,
+		MergedAt:       merge.MergedAt,
+	}
+}
+
+// mergeMembers folds the duplicate member into the survivor and archives the duplicate.
+func (app *application) mergeMembers(c echo.Context) error {
+	var req mergeMembersRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	var verr model.ValidationError
+	if req.SurvivorID == uuid.Nil {
+		verr.Add("survivor_id", model.CodeRequired, "Survivor ID is required")
+	}
+	switch {
+	case req.DuplicateID == uuid.Nil:
+		verr.Add("duplicate_id", model.CodeRequired, "Duplicate ID is required")
+	case req.DuplicateID == req.SurvivorID:
+		verr.Add("duplicate_id", model.CodeInvalidValue, "Duplicate must differ from the survivor")
+	}
+	if err := verr.Err(); err != nil {
+		return app.validationError(err)
+	}
+
+	merge := &model.MemberMerge{
+		SurvivorID:  req.SurvivorID,
+		DuplicateID: req.DuplicateID,
+		Actor:       actor(c),
+	}
+	if err := app.store.MergeMembers(c.Request().Context(), merge); err != nil {
+		app.logger.WriteError("Error merging members", err, map[string]interface{}{
+			"survivor_id":  req.SurvivorID,
+			"duplicate_id": req.DuplicateID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		case errors.Is(err, postgres.ErrMergeConflict):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Both members have a current membership of the same sport and type, cancel one first",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to merge members",
+			}
+		}
+	}
+
+	app.logger.WriteInfo("Merged members", map[string]interface{}{
+		"survivor_id":  merge.SurvivorID,
+		"duplicate_id": merge.DuplicateID,
+		"actor":        merge.Actor,
+	})
+
+	return c.JSON(http.StatusOK, newMemberMergeResponse(merge))
+}
+
+func (app *application) getMemberMerges(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	merges, err := app.store.GetMemberMerges(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting member merges", err, map[string]interface{}{
+			"id": id,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get member merges",
+		}
+	}
+
+	mergesResponse := make([]memberMergeResponse, len(merges))
+	for i, merge := range merges {
+		mergesResponse[i] = newMemberMergeResponse(merge)
+	}
+
+	return c.JSON(http.StatusOK, mergesResponse)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/blob"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/Ruthvik10/membership-managment-system/internal/photo"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	photoSizeDisplay   = "display"
+	photoSizeThumbnail = "thumbnail"
+)
+
+func memberPhotoKey(id uuid.UUID, size string) string {
+	return fmt.Sprintf("members/%s/photo-%s.jpg", id, size)
+}
+
+// memberPhotoURL returns where the member's photo is served, or nil if they have
+// none. The upload time is part of the URL so a new photo is never cached as the old.
+func memberPhotoURL(member *model.Member) *string {
+	if member.PhotoUpdatedAt == nil {
+		return nil
+	}
+	url := fmt.Sprintf("/api/v1/members/%s/photo?v=%d", member.ID, member.PhotoUpdatedAt.Unix())
+	return &url
+}
+
+// putMemberPhoto replaces the member's photo with the "photo" file of a multipart upload.
+func (app *application) putMemberPhoto(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+
+	// Leave room for the rest of the multipart body around the file.
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, app.photos.maxSize+1<<20)
+
+	var verr model.ValidationError
+	header, err := c.FormFile("photo")
+	switch {
+	case err != nil && errors.As(err, new(*http.MaxBytesError)):
+		verr.Add("photo", model.CodeOutOfRange, fmt.Sprintf("Photo must be at most %d bytes", app.photos.maxSize))
+	case err != nil:
+		verr.Add("photo", model.CodeRequired, "Photo is required")
+	case header.Size > app.photos.maxSize:
+		verr.Add("photo", model.CodeOutOfRange, fmt.Sprintf("Photo must be at most %d bytes", app.photos.maxSize))
+	}
+	if err := verr.Err(); err != nil {
+		return app.validationError(err)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		app.logger.WriteError("Error opening photo upload", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid photo upload",
+		}
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, app.photos.maxSize))
+	if err != nil {
+		app.logger.WriteError("Error reading photo upload", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid photo upload",
+		}
+	}
+
+	processed, err := photo.Process(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, photo.ErrUnsupportedType):
+			verr.Add("photo", model.CodeInvalidFormat, "Photo must be a JPEG or PNG image")
+		case errors.Is(err, photo.ErrTooManyPixels):
+			verr.Add("photo", model.CodeOutOfRange, fmt.Sprintf("Photo must have at most %d pixels", photo.MaxPixels))
+		default:
+			app.logger.WriteError("Error processing photo", err, map[string]interface{}{
+				"id": member.ID,
+			})
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to process photo",
+			}
+		}
+		return app.validationError(verr.Err())
+	}
+
+	images := map[string][]byte{
+		photoSizeDisplay:   processed.Display,
+		photoSizeThumbnail: processed.Thumbnail,
+	}
+	for size, image := range images {
+		if err := app.blobs.Put(c.Request().Context(), memberPhotoKey(member.ID, size), bytes.NewReader(image)); err != nil {
+			app.logger.WriteError("Error storing photo", err, map[string]interface{}{
+				"id":   member.ID,
+				"size": size,
+			})
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to store photo",
+			}
+		}
+	}
+
+	now := time.Now()
+	if err := app.store.SetMemberPhoto(c.Request().Context(), member.ID, &now); err != nil {
+		app.logger.WriteError("Error setting member photo", err, map[string]interface{}{
+			"id": member.ID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to set member photo",
+			}
+		}
+	}
+	member.PhotoUpdatedAt = &now
+
+	return c.JSON(http.StatusOK, newMemberResponse(member))
+}
+
+// getMemberPhoto serves the member's photo, or its thumbnail with size=thumbnail.
+func (app *application) getMemberPhoto(c echo.Context) error {
+	size := c.QueryParam("size")
+	switch size {
+	case "":
+		size = photoSizeDisplay
+	case photoSizeDisplay, photoSizeThumbnail:
+	default:
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid size, expected display or thumbnail",
+		}
+	}
+
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+	if member.PhotoUpdatedAt == nil {
+		return &echo.HTTPError{
+			Code:    http.StatusNotFound,
+			Message: "Member has no photo",
+		}
+	}
+
+	r, err := app.blobs.Get(c.Request().Context(), memberPhotoKey(member.ID, size))
+	if err != nil {
+		app.logger.WriteError("Error getting photo", err, map[string]interface{}{
+			"id":   member.ID,
+			"size": size,
+		})
+
+		switch {
+		case errors.Is(err, blob.ErrNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member has no photo",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get photo",
+			}
+		}
+	}
+	defer r.Close()
+
+	c.Response().Header().Set("Cache-Control", "private, max-age=86400")
+	return c.Stream(http.StatusOK, photo.ContentType, r)
+}
+
+func (app *application) deleteMemberPhoto(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+
+	if err := app.store.SetMemberPhoto(c.Request().Context(), member.ID, nil); err != nil {
+		app.logger.WriteError("Error removing member photo", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to remove member photo",
+		}
+	}
+
+	if err := app.removeMemberPhoto(c.Request().Context(), member.ID); err != nil {
+		app.logger.WriteError("Error deleting photo", err, map[string]interface{}{
+			"id": member.ID,
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// removeMemberPhoto deletes every size of the member's photo from blob storage.
+func (app *application) removeMemberPhoto(ctx context.Context, id uuid.UUID) error {
+	for _, size := range []string{photoSizeDisplay, photoSizeThumbnail} {
+		if err := app.blobs.Delete(ctx, memberPhotoKey(id, size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
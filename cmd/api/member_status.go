@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type memberStatusChangeResponse struct {
+	ID        uuid.UUID                `json:"id"`
+	From      string                   `json:"from"`
+	To        string                   `json:"to"`
+	Reason    model.MemberStatusReason `json:"reason"`
+	Note      string                   `json:"note"`
+	Actor     string                   `json:"actor"`
+	ChangedAt time.Time                `json:"changed_at"`
+}
+
+func (app *application) getMemberStatusHistory(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+
+	changes, err := app.store.GetMemberStatusHistory(c.Request().Context(), member.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting member status history", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get member status history",
+		}
+	}
+
+	historyResponse := make([]memberStatusChangeResponse, len(changes))
+	for i, change := range changes {
+		historyResponse[i] = memberStatusChangeResponse{
+			ID:        change.ID,
+			From:      model.MemberStatusMap[change.From],
+			To:        model.MemberStatusMap[change.To],
+			Reason:    change.Reason,
+			Note:      change.Note,
+			Actor:     change.Actor,
+			ChangedAt: change.ChangedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, historyResponse)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type memberTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type memberTagsResponse struct {
+	MemberID uuid.UUID `json:"member_id"`
+	Tags     []string  `json:"tags"`
+}
+
+func (app *application) getMemberTags(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+
+	return app.respondMemberTags(c, member.ID)
+}
+
+func (app *application) addMemberTags(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+	tags, err := app.memberTags(c)
+	if err != nil {
+		return err
+	}
+
+	if err := app.store.AddMemberTags(c.Request().Context(), member.ID, tags); err != nil {
+		app.logger.WriteError("Error adding member tags", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to add member tags",
+			}
+		}
+	}
+
+	return app.respondMemberTags(c, member.ID)
+}
+
+func (app *application) removeMemberTags(c echo.Context) error {
+	member, err := app.member(c)
+	if err != nil {
+		return err
+	}
+	tags, err := app.memberTags(c)
+	if err != nil {
+		return err
+	}
+
+	if err := app.store.RemoveMemberTags(c.Request().Context(), member.ID, tags); err != nil {
+		app.logger.WriteError("Error removing member tags", err, map[string]interface{}{
+			"id": member.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to remove member tags",
+		}
+	}
+
+	return app.respondMemberTags(c, member.ID)
+}
+
+// memberTags reads the normalized, distinct tags of the request body.
+func (app *application) memberTags(c echo.Context) ([]string, error) {
+	var req memberTagsRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	tags := make([]string, len(req.Tags))
+	for i, tag := range req.Tags {
+		tags[i] = model.NormalizeTag(tag)
+	}
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+
+	if len(tags) == 0 {
+		var verr model.ValidationError
+		verr.Add("tags", model.CodeRequired, "At least one tag is required")
+		return nil, app.validationError(&verr)
+	}
+	if err := model.ValidateTags("tags", tags); err != nil {
+		return nil, app.validationError(err)
+	}
+	return tags, nil
+}
+
+// respondMemberTags responds with the current tags of the member.
+func (app *application) respondMemberTags(c echo.Context, memberID uuid.UUID) error {
+	tags, err := app.store.GetMemberTags(c.Request().Context(), memberID)
+	if err != nil {
+		app.logger.WriteError("Error getting member tags", err, map[string]interface{}{
+			"id": memberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get member tags",
+		}
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return c.JSON(http.StatusOK, memberTagsResponse{MemberID: memberID, Tags: tags})
+}
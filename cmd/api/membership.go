@@ -1,41 +1,47 @@
 package main
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 func (app *application) registerMembershipRoutes(e *echo.Group) {
 	e.POST("/memberships", app.addMembership)
-	// e.GET("/memberships/:id", app.getMembershipByID)
-	// e.GET("/memberships", app.getAllMemberships)
-	// e.PATCH("/memberships/:id", app.updateMembership)
-	// e.DELETE("/memberships/:id", app.deleteMembership)
+	e.GET("/memberships/:id", app.getMembershipByID)
+	e.GET("/memberships", app.getAllMemberships)
+	e.PATCH("/memberships/:id", app.updateMembership)
+	e.DELETE("/memberships/:id", app.deleteMembership)
+	e.POST("/memberships/:id/status", app.transitionMembership)
+	e.GET("/memberships/:id/history", app.getMembershipHistory)
+	e.POST("/memberships/:id/change", app.changeMembership)
 }
 
 type addMembershipRequest struct {
-	MemberID  uuid.UUID              `json:"member_id"`
-	SportID   uuid.UUID              `json:"sport_id"`
-	Type      model.MembershipType   `json:"type"`
-	StartDate time.Time              `json:"start_date"`
-	DueDate   time.Time              `json:"due_date"`
-	Status    model.MembershipStatus `json:"status"`
-	Fee       float64                `json:"fee"`
+	MemberID  uuid.UUID  `json:"member_id"`
+	PlanID    uuid.UUID  `json:"plan_id"`
+	StartDate time.Time  `json:"start_date"`
+	AutoRenew bool       `json:"auto_renew"`
+	BatchID   *uuid.UUID `json:"batch_id"`
 }
 
 type addMembershipResponse struct {
-	ID        uuid.UUID              `json:"id"`
-	MemberID  uuid.UUID              `json:"member_id"`
-	SportID   uuid.UUID              `json:"sport_id"`
-	Type      model.MembershipType   `json:"type"`
-	StartDate time.Time              `json:"start_date"`
-	DueDate   time.Time              `json:"due_date"`
-	Status    model.MembershipStatus `json:"status"`
-	Fee       float64                `json:"fee"`
+	ID        uuid.UUID            `json:"id"`
+	MemberID  uuid.UUID            `json:"member_id"`
+	SportID   uuid.UUID            `json:"sport_id"`
+	PlanID    *uuid.UUID           `json:"plan_id"`
+	Type      model.MembershipType `json:"type"`
+	StartDate time.Time            `json:"start_date"`
+	DueDate   time.Time            `json:"due_date"`
+	Status    string               `json:"status"`
+	Fee       model.Money          `json:"fee"`
+	AutoRenew bool                 `json:"auto_renew"`
+	BatchID   *uuid.UUID           `json:"batch_id"`
 }
 
 func (app *application) addMembership(c echo.Context) error {
@@ -47,15 +53,10 @@ func (app *application) addMembership(c echo.Context) error {
 		}
 	}
 
-	if req.Fee <= 0 || req.Type == "" || req.Status < 0 || req.MemberID == uuid.Nil || req.SportID == uuid.Nil || req.StartDate.IsZero() || req.DueDate.IsZero() {
+	if req.MemberID == uuid.Nil || req.PlanID == uuid.Nil {
 		app.logger.WriteError("Missing required fields", nil, map[string]interface{}{
-			"fee":        req.Fee,
-			"type":       req.Type,
-			"status":     req.Status,
-			"member_id":  req.MemberID,
-			"sport_id":   req.SportID,
-			"start_date": req.StartDate,
-			"due_date":   req.DueDate,
+			"member_id": req.MemberID,
+			"plan_id":   req.PlanID,
 		})
 		return &echo.HTTPError{
 			Code:    http.StatusBadRequest,
@@ -63,48 +64,345 @@ func (app *application) addMembership(c echo.Context) error {
 		}
 	}
 
-	membership := &model.Membership{
-		MemberID:  req.MemberID,
-		SportID:   req.SportID,
-		Type:      req.Type,
-		StartDate: req.StartDate,
-		DueDate:   req.DueDate,
-		Status:    req.Status,
-		Fee:       req.Fee,
-	}
-
-	if !membership.Valid() {
-		app.logger.WriteError("Invalid membership details", nil, map[string]interface{}{
-			"fee":        req.Fee,
-			"type":       req.Type,
-			"status":     req.Status,
-			"member_id":  req.MemberID,
-			"sport_id":   req.SportID,
-			"start_date": req.StartDate,
-			"due_date":   req.DueDate,
+	plan, err := app.store.GetPlanByID(c.Request().Context(), req.PlanID)
+	if err != nil {
+		app.logger.WriteError("Error getting plan", err, map[string]interface{}{
+			"plan_id": req.PlanID,
 		})
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid membership",
+
+		switch {
+		case errors.Is(err, postgres.ErrPlanNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Plan not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get plan",
+			}
 		}
 	}
 
-	if err := app.store.AddMembership(c.Request().Context(), membership); err != nil {
+	if req.StartDate.IsZero() {
+		req.StartDate = time.Now()
+	}
+
+	membership := plan.NewMembership(req.MemberID, req.StartDate)
+	membership.AutoRenew = req.AutoRenew
+	membership.BatchID = req.BatchID
+
+	if err := membership.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddMembership(c.Request().Context(), membership, actor(c)); err != nil {
 		app.logger.WriteError("Error adding membership", err, nil)
+		switch {
+		case errors.Is(err, postgres.ErrMembershipAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership already exists",
+			}
+		case errors.Is(err, postgres.ErrMembershipReferenceNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Member or sport not found",
+			}
+		case errors.Is(err, postgres.ErrBatchNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Batch not found",
+			}
+		case errors.Is(err, postgres.ErrBatchSportMismatch):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Batch is not of the membership's sport",
+			}
+		case errors.Is(err, postgres.ErrBatchFull):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Batch is full",
+			}
+		case errors.Is(err, postgres.ErrMissingRequiredField):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Missing required fields",
+			}
+		}
 		return &echo.HTTPError{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to add membership",
 		}
 	}
 
-	return c.JSON(http.StatusCreated, addMembershipResponse{
+	return c.JSON(http.StatusCreated, newMembershipResponse(membership))
+}
+
+type getMembershipResponse = addMembershipResponse
+
+func newMembershipResponse(membership *model.Membership) getMembershipResponse {
+	return getMembershipResponse{
 		ID:        membership.ID,
 		MemberID:  membership.MemberID,
 		SportID:   membership.SportID,
+		PlanID:    membership.PlanID,
 		Type:      membership.Type,
 		StartDate: membership.StartDate,
 		DueDate:   membership.DueDate,
-		Status:    membership.Status,
+		Status:    model.MembershipStatusMap[membership.Status],
 		Fee:       membership.Fee,
-	})
+		AutoRenew: membership.AutoRenew,
+		BatchID:   membership.BatchID,
+	}
+}
+
+func (app *application) getMembershipByID(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid membership ID",
+		}
+	}
+
+	membership, err := app.store.GetMembershipByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting membership", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Membership not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get membership",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newMembershipResponse(membership))
+}
+
+func (app *application) getAllMemberships(c echo.Context) error {
+	memberships, err := app.store.GetAllMemberships(c.Request().Context())
+	if err != nil {
+		app.logger.WriteError("Error getting memberships", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get memberships",
+		}
+	}
+
+	membershipsResponse := make([]getMembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		membershipsResponse[i] = newMembershipResponse(membership)
+	}
+
+	return c.JSON(http.StatusOK, membershipsResponse)
+}
+
+// updateMembershipRequest changes the membership. BatchID moves it to another
+// batch of its sport. The type, period and fee come from the plan and are only
+// changed by changing the plan.
+type updateMembershipRequest struct {
+	AutoRenew *bool      `json:"auto_renew"`
+	BatchID   *uuid.UUID `json:"batch_id"`
+}
+
+func (app *application) updateMembership(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid membership ID",
+		}
+	}
+	var req updateMembershipRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error binding request", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to bind request",
+		}
+	}
+
+	membership, err := app.store.GetMembershipByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting membership", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Membership not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get membership",
+			}
+		}
+	}
+
+	if req.AutoRenew != nil {
+		membership.AutoRenew = *req.AutoRenew
+	}
+	if req.BatchID != nil {
+		membership.BatchID = req.BatchID
+	}
+
+	if err := membership.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.UpdateMembership(c.Request().Context(), membership); err != nil {
+		app.logger.WriteError("Error updating membership", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership already exists",
+			}
+		case errors.Is(err, postgres.ErrMembershipNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Membership not found",
+			}
+		case errors.Is(err, postgres.ErrBatchNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Batch not found",
+			}
+		case errors.Is(err, postgres.ErrBatchSportMismatch):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Batch is not of the membership's sport",
+			}
+		case errors.Is(err, postgres.ErrBatchFull):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Batch is full",
+			}
+		case errors.Is(err, postgres.ErrMissingRequiredField):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Missing required fields",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update membership",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newMembershipResponse(membership))
+}
+
+// deleteMembership cancels the membership. Memberships are never removed, so
+// their payments and status history are kept. The optional reason query
+// parameter is recorded with the transition.
+func (app *application) deleteMembership(c echo.Context) error {
+	membership, err := app.membership(c)
+	if err != nil {
+		return err
+	}
+
+	reason := c.QueryParam("reason")
+	if reason == "" {
+		reason = "cancelled"
+	}
+
+	transition, err := membership.Transition(model.MembershipCancelled, actor(c), reason)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+		}
+	}
+
+	if err := app.store.TransitionMembership(c.Request().Context(), membership, transition); err != nil {
+		app.logger.WriteError("Error cancelling membership", err, map[string]interface{}{
+			"id": membership.ID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership status changed, try again",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to cancel membership",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newMembershipResponse(membership))
+}
+
+// membership loads the membership addressed by the :id path parameter.
+func (app *application) membership(c echo.Context) (*model.Membership, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid membership ID",
+		}
+	}
+
+	membership, err := app.store.GetMembershipByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting membership", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipNotFound):
+			return nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Membership not found",
+			}
+		default:
+			return nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get membership",
+			}
+		}
+	}
+
+	return membership, nil
+}
+
+// membershipPlan loads the plan of the membership, or returns nil if it has none.
+func (app *application) membershipPlan(c echo.Context, membership *model.Membership) (*model.Plan, error) {
+	if membership.PlanID == nil {
+		return nil, nil
+	}
+
+	plan, err := app.store.GetPlanByID(c.Request().Context(), *membership.PlanID)
+	if err != nil {
+		app.logger.WriteError("Error getting plan", err, map[string]interface{}{
+			"id": *membership.PlanID,
+		})
+		return nil, &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get plan",
+		}
+	}
+
+	return plan, nil
 }
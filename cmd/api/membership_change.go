@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type changeMembershipRequest struct {
+	PlanID uuid.UUID `json:"plan_id"`
+	DryRun bool      `json:"dry_run"`
+}
+
+type prorationResponse struct {
+	Date          time.Time   `json:"date"`
+	TotalDays     int         `json:"total_days"`
+	UsedDays      int         `json:"used_days"`
+	RemainingDays int         `json:"remaining_days"`
+	Fee           model.Money `json:"fee"`
+	Paid          model.Money `json:"paid"`
+	Used          model.Money `json:"used"`
+	Credit        model.Money `json:"credit"`
+	Price         model.Money `json:"price"`
+	AmountDue     model.Money `json:"amount_due"`
+	UnusedCredit  model.Money `json:"unused_credit"`
+}
+
+type changeMembershipResponse struct {
+	DryRun    bool                  `json:"dry_run"`
+	Current   getMembershipResponse `json:"current"`
+	Next      getMembershipResponse `json:"next"`
+	Breakdown prorationResponse     `json:"breakdown"`
+}
+
+// changeMembership moves a membership to another plan before its due date. The
+// current membership is cancelled and the value of its unused days is credited
+// against the fee of the new one. Credit beyond that fee is kept as member credit,
+// which is applied to the member's next invoice. With dry_run set, only the
+// breakdown is returned.
+func (app *application) changeMembership(c echo.Context) error {
+	var req changeMembershipRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	if req.PlanID == uuid.Nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Missing required fields",
+		}
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid membership ID",
+		}
+	}
+
+	membership, payments, err := app.membershipPayments(c, id)
+	if err != nil {
+		return err
+	}
+
+	if membership.PlanID != nil && *membership.PlanID == req.PlanID {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Membership is already on this plan",
+		}
+	}
+
+	// Frozen memberships have a freeze in progress, which has to be ended first.
+	if membership.Status == model.MembershipFrozen {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Unfreeze the membership before changing it",
+		}
+	}
+
+	transition, err := membership.Transition(model.MembershipCancelled, actor(c), "changed to plan "+req.PlanID.String())
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+		}
+	}
+
+	plan, err := app.store.GetPlanByID(c.Request().Context(), req.PlanID)
+	if err != nil {
+		app.logger.WriteError("Error getting plan", err, map[string]interface{}{
+			"plan_id": req.PlanID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrPlanNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Plan not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get plan",
+			}
+		}
+	}
+
+	proration, err := model.Prorate(membership, payments, plan, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrMembershipPastDue):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership is past its due date, renew it instead",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Plan is priced in another currency than the membership",
+			}
+		}
+	}
+	next := proration.NewMembership(membership, plan)
+	credit := proration.MemberCredit(membership, "unused credit of membership changed to plan "+req.PlanID.String())
+
+	if !req.DryRun {
+		if err := app.store.ChangeMembership(c.Request().Context(), membership, next, transition, credit); err != nil {
+			app.logger.WriteError("Error changing membership", err, map[string]interface{}{
+				"id":      membership.ID,
+				"plan_id": req.PlanID,
+			})
+			switch {
+			case errors.Is(err, postgres.ErrMembershipStatusChanged):
+				return &echo.HTTPError{
+					Code:    http.StatusConflict,
+					Message: "Membership status changed, try again",
+				}
+			case errors.Is(err, postgres.ErrMembershipAlreadyExists):
+				return &echo.HTTPError{
+					Code:    http.StatusConflict,
+					Message: "Member already has a current membership of this sport and type",
+				}
+			default:
+				return &echo.HTTPError{
+					Code:    http.StatusInternalServerError,
+					Message: "Failed to change membership",
+				}
+			}
+		}
+	}
+
+	code := http.StatusCreated
+	if req.DryRun {
+		code = http.StatusOK
+	}
+	return c.JSON(code, changeMembershipResponse{
+		DryRun:  req.DryRun,
+		Current: newMembershipResponse(membership),
+		Next:    newMembershipResponse(next),
+		Breakdown: prorationResponse{
+			Date:          proration.Date,
+			TotalDays:     proration.TotalDays,
+			UsedDays:      proration.UsedDays,
+			RemainingDays: proration.RemainingDays,
+			Fee:           proration.Fee,
+			Paid:          proration.Paid,
+			Used:          proration.Used,
+			Credit:        proration.Credit,
+			Price:         proration.Price,
+			AmountDue:     proration.AmountDue,
+			UnusedCredit:  proration.UnusedCredit,
+		},
+	})
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// headerActor names the staff member or system performing a request. It is
+// recorded in the history of the changes the request makes.
+const headerActor = "X-Actor"
+
+func actor(c echo.Context) string {
+	if actor := c.Request().Header.Get(headerActor); actor != "" {
+		return actor
+	}
+	return "api"
+}
+
+type transitionMembershipRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (app *application) transitionMembership(c echo.Context) error {
+	var req transitionMembershipRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	status, ok := model.ParseMembershipStatus(req.Status)
+	if !ok || req.Reason == "" {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "A valid status and a reason are required",
+		}
+	}
+
+	// Freezes extend the due date, so they go through the freeze endpoints.
+	if status == model.MembershipFrozen {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Use the freeze endpoint to freeze a membership",
+		}
+	}
+
+	membership, err := app.membership(c)
+	if err != nil {
+		return err
+	}
+
+	// Unfreezing credits the frozen days, so it goes through the unfreeze endpoint.
+	if membership.Status == model.MembershipFrozen && status == model.MembershipActive {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Use the unfreeze endpoint to end a freeze",
+		}
+	}
+
+	transition, err := membership.Transition(status, actor(c), req.Reason)
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+		}
+	}
+
+	if err := app.store.TransitionMembership(c.Request().Context(), membership, transition); err != nil {
+		app.logger.WriteError("Error transitioning membership", err, map[string]interface{}{
+			"id":     membership.ID,
+			"status": req.Status,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrMembershipStatusChanged):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Membership status changed, try again",
+			}
+		case errors.Is(err, postgres.ErrMembershipAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Member already has a current membership of this sport and type",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update membership status",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, newMembershipResponse(membership))
+}
+
+type getMembershipTransitionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	From      *string   `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (app *application) getMembershipHistory(c echo.Context) error {
+	membership, err := app.membership(c)
+	if err != nil {
+		return err
+	}
+
+	transitions, err := app.store.GetMembershipHistory(c.Request().Context(), membership.ID)
+	if err != nil {
+		app.logger.WriteError("Error getting membership history", err, map[string]interface{}{
+			"id": membership.ID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get membership history",
+		}
+	}
+
+	historyResponse := make([]getMembershipTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		historyResponse[i] = getMembershipTransitionResponse{
+			ID:        transition.ID,
+			To:        model.MembershipStatusMap[transition.To],
+			Actor:     transition.Actor,
+			Reason:    transition.Reason,
+			ChangedAt: transition.ChangedAt,
+		}
+		if transition.From != nil {
+			from := model.MembershipStatusMap[*transition.From]
+			historyResponse[i].From = &from
+		}
+	}
+
+	return c.JSON(http.StatusOK, historyResponse)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerPaymentRoutes(e *echo.Group) {
+	e.POST("/payments", app.addPayment)
+	e.GET("/payments/:id", app.getPaymentByID)
+	e.GET("/memberships/:id/payments", app.getMembershipPayments)
+}
+
+type addPaymentRequest struct {
+	MembershipID uuid.UUID            `json:"membership_id"`
+	Amount       model.Money          `json:"amount"`
+	PaymentDate  time.Time            `json:"payment_date"`
+	Status       *model.PaymentStatus `json:"status"`
+	PaymentLink  string               `json:"payment_link"`
+}
+
+type getPaymentResponse struct {
+	ID           uuid.UUID   `json:"id"`
+	MembershipID uuid.UUID   `json:"membership_id"`
+	Amount       model.Money `json:"amount"`
+	PaymentDate  time.Time   `json:"payment_date"`
+	Status       string      `json:"status"`
+	PaymentLink  string      `json:"payment_link,omitempty"`
+}
+
+func (app *application) addPayment(c echo.Context) error {
+	var req addPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	payment := &model.Payment{
+		MembershipID: req.MembershipID,
+		Amount:       req.Amount,
+		PaymentDate:  req.PaymentDate,
+		Status:       model.PaymentStatusCompleted,
+		PaymentLink:  req.PaymentLink,
+	}
+	if req.Status != nil {
+		payment.Status = *req.Status
+	}
+	if payment.PaymentDate.IsZero() {
+		payment.PaymentDate = time.Now()
+	}
+
+	if err := payment.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddPayment(c.Request().Context(), payment); err != nil {
+		app.logger.WriteError("Error adding payment", err, map[string]interface{}{
+			"membership_id": payment.MembershipID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Membership not found",
+			}
+		case errors.Is(err, model.ErrCurrencyMismatch):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Payment currency does not match the membership fee",
+			}
+		case errors.Is(err, postgres.ErrPaymentExceedsBalance):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Payment exceeds the outstanding balance",
+			}
+		case errors.Is(err, postgres.ErrMissingRequiredField):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Required fields missing",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to add payment",
+			}
+		}
+	}
+
+	if payment.Status == model.PaymentStatusCompleted {
+		if membership, payments, err := app.membershipPayments(c, payment.MembershipID); err == nil {
+			app.activatePaidMembership(c, membership, payments)
+		}
+	}
+
+	return c.JSON(http.StatusCreated, getPaymentResponse{
+		ID:           payment.ID,
+		MembershipID: payment.MembershipID,
+		Amount:       payment.Amount,
+		PaymentDate:  payment.PaymentDate,
+		Status:       model.PaymentStatusMap[payment.Status],
+		PaymentLink:  payment.PaymentLink,
+	})
+}
+
+func (app *application) getPaymentByID(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid payment ID",
+		}
+	}
+
+	payment, err := app.store.GetPaymentByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting payment", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrPaymentNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Payment not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get payment",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, getPaymentResponse{
+		ID:           payment.ID,
+		MembershipID: payment.MembershipID,
+		Amount:       payment.Amount,
+		PaymentDate:  payment.PaymentDate,
+		Status:       model.PaymentStatusMap[payment.Status],
+		PaymentLink:  payment.PaymentLink,
+	})
+}
+
+type getMembershipPaymentsResponse struct {
+	MembershipID uuid.UUID            `json:"membership_id"`
+	Fee          model.Money          `json:"fee"`
+	Paid         model.Money          `json:"paid"`
+	Balance      model.Money          `json:"balance"`
+	Payments     []getPaymentResponse `json:"payments"`
+}
+
+func (app *application) getMembershipPayments(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid membership ID",
+		}
+	}
+
+	membership, payments, err := app.membershipPayments(c, id)
+	if err != nil {
+		return err
+	}
+
+	balance := model.Balance(membership, payments)
+	paymentsResponse := make([]getPaymentResponse, len(payments))
+	for i, payment := range payments {
+		paymentsResponse[i] = getPaymentResponse{
+			ID:           payment.ID,
+			MembershipID: payment.MembershipID,
+			Amount:       payment.Amount,
+			PaymentDate:  payment.PaymentDate,
+			Status:       model.PaymentStatusMap[payment.Status],
+			PaymentLink:  payment.PaymentLink,
+		}
+	}
+
+	return c.JSON(http.StatusOK, getMembershipPaymentsResponse{
+		MembershipID: membership.ID,
+		Fee:          membership.Fee,
+		Paid:         membership.Fee.Sub(balance),
+		Balance:      balance,
+		Payments:     paymentsResponse,
+	})
+}
+
+// membershipPayments loads a membership together with its payment history and
+// converts store errors into HTTP errors.
+func (app *application) membershipPayments(c echo.Context, id uuid.UUID) (*model.Membership, []*model.Payment, error) {
+	membership, err := app.store.GetMembershipByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting membership", err, map[string]interface{}{
+			"id": id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMembershipNotFound):
+			return nil, nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Membership not found",
+			}
+		default:
+			return nil, nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get membership",
+			}
+		}
+	}
+
+	payments, err := app.store.GetPaymentsByMembershipID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting payments", err, map[string]interface{}{
+			"membership_id": id,
+		})
+		return nil, nil, &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get payments",
+		}
+	}
+
+	return membership, payments, nil
+}
+
+// activatePaidMembership activates a membership awaiting payment once its fee is
+// paid in full. The payment is already recorded, so a failure is only logged.
+func (app *application) activatePaidMembership(c echo.Context, membership *model.Membership, payments []*model.Payment) {
+	if membership.Status != model.MembershipPendingPayment || model.Balance(membership, payments).IsPositive() {
+		return
+	}
+
+	transition, err := membership.Transition(model.MembershipActive, actor(c), "paid in full")
+	if err != nil {
+		return
+	}
+	if err := app.store.TransitionMembership(c.Request().Context(), membership, transition); err != nil {
+		app.logger.WriteError("Error activating paid membership", err, map[string]interface{}{
+			"id": membership.ID,
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerPlanRoutes(e *echo.Group) {
+	e.POST("/sports/:id/plans", app.addPlan)
+	e.GET("/sports/:id/plans", app.getSportPlans)
+	e.GET("/sports/:id/plans/:plan_id", app.getPlanByID)
+	e.PATCH("/sports/:id/plans/:plan_id", app.updatePlan)
+	e.DELETE("/sports/:id/plans/:plan_id", app.deletePlan)
+}
+
+type addPlanRequest struct {
+	Name          string               `json:"name"`
+	BillingPeriod model.BillingPeriod  `json:"billing_period"`
+	Price         model.Money          `json:"price"`
+	Type          model.MembershipType `json:"type"`
+	MaxFreezeDays int                  `json:"max_freeze_days"`
+	MinFreezeDays int                  `json:"min_freeze_days"`
+}
+
+type getPlanResponse struct {
+	ID            uuid.UUID            `json:"id"`
+	SportID       uuid.UUID            `json:"sport_id"`
+	Name          string               `json:"name"`
+	BillingPeriod model.BillingPeriod  `json:"billing_period"`
+	Price         model.Money          `json:"price"`
+	Type          model.MembershipType `json:"type"`
+	MaxFreezeDays int                  `json:"max_freeze_days"`
+	MinFreezeDays int                  `json:"min_freeze_days"`
+}
+
+func (app *application) addPlan(c echo.Context) error {
+	sportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid sport ID",
+		}
+	}
+
+	var req addPlanRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	plan := &model.Plan{
+		SportID:       sportID,
+		Name:          req.Name,
+		BillingPeriod: req.BillingPeriod,
+		Price:         req.Price,
+		Type:          req.Type,
+		MaxFreezeDays: req.MaxFreezeDays,
+		MinFreezeDays: req.MinFreezeDays,
+	}
+
+	if err := plan.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.AddPlan(c.Request().Context(), plan); err != nil {
+		app.logger.WriteError("Error adding plan", err, map[string]interface{}{
+			"sport_id": sportID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrPlanAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Plan already exists",
+			}
+		case errors.Is(err, postgres.ErrSportNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Sport not found",
+			}
+		case errors.Is(err, postgres.ErrMissingRequiredField):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Missing required field",
+			}
+		}
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to add plan",
+		}
+	}
+
+	return c.JSON(http.StatusCreated, getPlanResponse{
+		ID:            plan.ID,
+		SportID:       plan.SportID,
+		Name:          plan.Name,
+		BillingPeriod: plan.BillingPeriod,
+		Price:         plan.Price,
+		Type:          plan.Type,
+		MaxFreezeDays: plan.MaxFreezeDays,
+		MinFreezeDays: plan.MinFreezeDays,
+	})
+}
+
+func (app *application) getSportPlans(c echo.Context) error {
+	sportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid sport ID",
+		}
+	}
+
+	plans, err := app.store.GetPlansBySportID(c.Request().Context(), sportID)
+	if err != nil {
+		app.logger.WriteError("Error getting plans", err, map[string]interface{}{
+			"sport_id": sportID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get plans",
+		}
+	}
+
+	plansResponse := make([]getPlanResponse, len(plans))
+	for i, plan := range plans {
+		plansResponse[i] = getPlanResponse{
+			ID:            plan.ID,
+			SportID:       plan.SportID,
+			Name:          plan.Name,
+			BillingPeriod: plan.BillingPeriod,
+			Price:         plan.Price,
+			Type:          plan.Type,
+			MaxFreezeDays: plan.MaxFreezeDays,
+			MinFreezeDays: plan.MinFreezeDays,
+		}
+	}
+
+	return c.JSON(http.StatusOK, plansResponse)
+}
+
+func (app *application) getPlanByID(c echo.Context) error {
+	plan, err := app.sportPlan(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, getPlanResponse{
+		ID:            plan.ID,
+		SportID:       plan.SportID,
+		Name:          plan.Name,
+		BillingPeriod: plan.BillingPeriod,
+		Price:         plan.Price,
+		Type:          plan.Type,
+		MaxFreezeDays: plan.MaxFreezeDays,
+		MinFreezeDays: plan.MinFreezeDays,
+	})
+}
+
+type updatePlanRequest struct {
+	Name          *string               `json:"name"`
+	BillingPeriod *model.BillingPeriod  `json:"billing_period"`
+	Price         *model.Money          `json:"price"`
+	Type          *model.MembershipType `json:"type"`
+	MaxFreezeDays *int                  `json:"max_freeze_days"`
+	MinFreezeDays *int                  `json:"min_freeze_days"`
+}
+
+func (app *application) updatePlan(c echo.Context) error {
+	var req updatePlanRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error binding request", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to bind request",
+		}
+	}
+
+	plan, err := app.sportPlan(c)
+	if err != nil {
+		return err
+	}
+
+	if req.Name != nil {
+		plan.Name = *req.Name
+	}
+	if req.BillingPeriod != nil {
+		plan.BillingPeriod = *req.BillingPeriod
+	}
+	if req.Price != nil {
+		plan.Price = *req.Price
+	}
+	if req.Type != nil {
+		plan.Type = *req.Type
+	}
+	if req.MaxFreezeDays != nil {
+		plan.MaxFreezeDays = *req.MaxFreezeDays
+	}
+	if req.MinFreezeDays != nil {
+		plan.MinFreezeDays = *req.MinFreezeDays
+	}
+
+	if err := plan.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	if err := app.store.UpdatePlan(c.Request().Context(), plan); err != nil {
+		app.logger.WriteError("Error updating plan", err, map[string]interface{}{
+			"id": plan.ID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrPlanAlreadyExists):
+			return &echo.HTTPError{
+				Code:    http.StatusConflict,
+				Message: "Plan already exists",
+			}
+		case errors.Is(err, postgres.ErrPlanNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Plan not found",
+			}
+		case errors.Is(err, postgres.ErrMissingRequiredField):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Missing required field",
+			}
+		}
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to update plan",
+		}
+	}
+
+	return c.JSON(http.StatusOK, getPlanResponse{
+		ID:            plan.ID,
+		SportID:       plan.SportID,
+		Name:          plan.Name,
+		BillingPeriod: plan.BillingPeriod,
+		Price:         plan.Price,
+		Type:          plan.Type,
+		MaxFreezeDays: plan.MaxFreezeDays,
+		MinFreezeDays: plan.MinFreezeDays,
+	})
+}
+
+func (app *application) deletePlan(c echo.Context) error {
+	plan, err := app.sportPlan(c)
+	if err != nil {
+		return err
+	}
+
+	if err := app.store.DeletePlan(c.Request().Context(), plan.ID); err != nil {
+		app.logger.WriteError("Error deleting plan", err, map[string]interface{}{
+			"id": plan.ID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrPlanNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Plan not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete plan",
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// sportPlan loads the plan addressed by the :id and :plan_id path parameters,
+// treating a plan of another sport as not found.
+func (app *application) sportPlan(c echo.Context) (*model.Plan, error) {
+	sportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid sport ID",
+		}
+	}
+	id, err := uuid.Parse(c.Param("plan_id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid plan ID",
+		}
+	}
+
+	plan, err := app.store.GetPlanByID(c.Request().Context(), id)
+	if err == nil && plan.SportID != sportID {
+		err = postgres.ErrPlanNotFound
+	}
+	if err != nil {
+		app.logger.WriteError("Error getting plan", err, map[string]interface{}{
+			"id":       id,
+			"sport_id": sportID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrPlanNotFound):
+			return nil, &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Plan not found",
+			}
+		default:
+			return nil, &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get plan",
+			}
+		}
+	}
+
+	return plan, nil
+}
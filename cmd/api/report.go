@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerReportRoutes(e *echo.Group) {
+	e.GET("/reports/dues", app.getDuesReport)
+}
+
+type duesReportRow struct {
+	MemberID     uuid.UUID         `json:"member_id"`
+	MemberName   string            `json:"member_name"`
+	Email        string            `json:"email"`
+	PhoneNumber  string            `json:"phone_number"`
+	MembershipID uuid.UUID         `json:"membership_id"`
+	SportID      uuid.UUID         `json:"sport_id"`
+	SportName    string            `json:"sport_name"`
+	DueDate      time.Time         `json:"due_date"`
+	Fee          model.Money       `json:"fee"`
+	Paid         model.Money       `json:"paid"`
+	Outstanding  model.Money       `json:"outstanding"`
+	DaysOverdue  int               `json:"days_overdue"`
+	AgingBucket  model.AgingBucket `json:"aging_bucket"`
+}
+
+// duesReportSummary totals the outstanding amounts of one currency per aging bucket.
+type duesReportSummary struct {
+	Currency string                            `json:"currency"`
+	Buckets  map[model.AgingBucket]model.Money `json:"buckets"`
+	Total    model.Money                       `json:"total"`
+}
+
+type duesReportResponse struct {
+	AsOf    time.Time           `json:"as_of"`
+	Summary []duesReportSummary `json:"summary"`
+	Rows    []duesReportRow     `json:"rows"`
+}
+
+// getDuesReport lists the memberships with an outstanding balance, grouped into
+// aging buckets by days overdue; dues not yet due are current. It returns CSV when
+// format=csv is given or the client accepts text/csv, and JSON otherwise.
+func (app *application) getDuesReport(c echo.Context) error {
+	var sportID *uuid.UUID
+	if param := c.QueryParam("sport_id"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid sport ID",
+			}
+		}
+		sportID = &id
+	}
+
+	format := c.QueryParam("format")
+	if format == "" && strings.Contains(c.Request().Header.Get(echo.HeaderAccept), "text/csv") {
+		format = "csv"
+	}
+	if format != "" && format != "json" && format != "csv" {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid format, expected json or csv",
+		}
+	}
+
+	dues, err := app.store.GetOutstandingDues(c.Request().Context(), sportID)
+	if err != nil {
+		app.logger.WriteError("Error getting outstanding dues", err, map[string]interface{}{
+			"sport_id": sportID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get dues report",
+		}
+	}
+
+	report := newDuesReport(dues, time.Now())
+
+	if format == "csv" {
+		body, err := report.csv()
+		if err != nil {
+			app.logger.WriteError("Error writing dues report", err, nil)
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to write dues report",
+			}
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "dues-"+report.AsOf.Format("2006-01-02")+".csv"))
+		return c.Blob(http.StatusOK, "text/csv", body)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+func newDuesReport(dues []*model.Due, now time.Time) duesReportResponse {
+	report := duesReportResponse{
+		AsOf:    now,
+		Summary: []duesReportSummary{},
+		Rows:    make([]duesReportRow, len(dues)),
+	}
+
+	summaries := make(map[string]int)
+	for i, due := range dues {
+		row := duesReportRow{
+			MemberID:     due.MemberID,
+			MemberName:   due.MemberName,
+			Email:        due.Email,
+			PhoneNumber:  due.PhoneNumber,
+			MembershipID: due.MembershipID,
+			SportID:      due.SportID,
+			SportName:    due.SportName,
+			DueDate:      due.DueDate,
+			Fee:          due.Fee,
+			Paid:         due.Paid,
+			Outstanding:  due.Outstanding(),
+			DaysOverdue:  due.DaysOverdue(now),
+			AgingBucket:  due.AgingBucket(now),
+		}
+		report.Rows[i] = row
+
+		currency := row.Outstanding.Currency
+		j, ok := summaries[currency]
+		if !ok {
+			summary := duesReportSummary{
+				Currency: currency,
+				Buckets:  make(map[model.AgingBucket]model.Money, len(model.AgingBuckets)),
+				Total:    model.Money{Currency: currency},
+			}
+			for _, bucket := range model.AgingBuckets {
+				summary.Buckets[bucket] = model.Money{Currency: currency}
+			}
+			j = len(report.Summary)
+			summaries[currency] = j
+			report.Summary = append(report.Summary, summary)
+		}
+		summary := &report.Summary[j]
+		summary.Buckets[row.AgingBucket] = summary.Buckets[row.AgingBucket].Add(row.Outstanding)
+		summary.Total = summary.Total.Add(row.Outstanding)
+	}
+
+	return report
+}
+
+func (r duesReportResponse) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{{
+		"member_id", "member_name", "email", "phone_number", "membership_id", "sport_id", "sport_name",
+		"due_date", "currency", "fee", "paid", "outstanding", "days_overdue", "aging_bucket",
+	}}
+	for _, row := range r.Rows {
+		records = append(records, []string{
+			row.MemberID.String(),
+			row.MemberName,
+			row.Email,
+			row.PhoneNumber,
+			row.MembershipID.String(),
+			row.SportID.String(),
+			row.SportName,
+			row.DueDate.Format("2006-01-02"),
+			row.Fee.Currency,
+			row.Fee.Decimal(),
+			row.Paid.Decimal(),
+			row.Outstanding.Decimal(),
+			strconv.Itoa(row.DaysOverdue),
+			string(row.AgingBucket),
+		})
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func (app *application) registerSegmentRoutes(e *echo.Group) {
+	e.POST("/segments", app.addSegment)
+	e.GET("/segments", app.getSegments)
+	e.GET("/segments/:id", app.getSegmentByID)
+	e.PATCH("/segments/:id", app.updateSegment)
+	e.DELETE("/segments/:id", app.deleteSegment)
+	e.GET("/segments/:id/members", app.getSegmentMembers)
+}
+
+// segmentFilter is the API form of model.SegmentFilter, with statuses by name.
+type segmentFilter struct {
+	Status           string         `json:"status,omitempty"`
+	Tags             []string       `json:"tags,omitempty"`
+	CustomFields     map[string]any `json:"custom_fields,omitempty"`
+	JoinedAfter      *time.Time     `json:"joined_after,omitempty"`
+	JoinedBefore     *time.Time     `json:"joined_before,omitempty"`
+	SportID          *uuid.UUID     `json:"sport_id,omitempty"`
+	MembershipStatus string         `json:"membership_status,omitempty"`
+	Owing            *bool          `json:"owing,omitempty"`
+}
+
+func newSegmentFilter(f model.SegmentFilter) segmentFilter {
+	filter := segmentFilter{
+		Tags:         f.Tags,
+		CustomFields: f.CustomFields,
+		JoinedAfter:  f.JoinedAfter,
+		JoinedBefore: f.JoinedBefore,
+		SportID:      f.SportID,
+		Owing:        f.Owing,
+	}
+	if f.Status != nil {
+		filter.Status = model.MemberStatusMap[*f.Status]
+	}
+	if f.MembershipStatus != nil {
+		filter.MembershipStatus = model.MembershipStatusMap[*f.MembershipStatus]
+	}
+	return filter
+}
+
+// parse returns the filter with its statuses resolved and its tags normalized.
+func (f segmentFilter) parse() (model.SegmentFilter, error) {
+	filter := model.SegmentFilter{
+		CustomFields: f.CustomFields,
+		JoinedAfter:  f.JoinedAfter,
+		JoinedBefore: f.JoinedBefore,
+		SportID:      f.SportID,
+		Owing:        f.Owing,
+	}
+	for _, tag := range f.Tags {
+		filter.Tags = append(filter.Tags, model.NormalizeTag(tag))
+	}
+
+	var verr model.ValidationError
+	if f.Status != "" {
+		for status, name := range model.MemberStatusMap {
+			if strings.EqualFold(name, f.Status) {
+				filter.Status = &status
+			}
+		}
+		if filter.Status == nil {
+			verr.Add("filter.status", model.CodeInvalidValue, "Status is not a known member status")
+		}
+	}
+	if f.MembershipStatus != "" {
+		if status, ok := model.ParseMembershipStatus(f.MembershipStatus); ok {
+			filter.MembershipStatus = &status
+		} else {
+			verr.Add("filter.membership_status", model.CodeInvalidValue, "Membership status is not a known membership status")
+		}
+	}
+	return filter, verr.Err()
+}
+
+type segmentRequest struct {
+	Name   string        `json:"name"`
+	Filter segmentFilter `json:"filter"`
+}
+
+type segmentResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Filter    segmentFilter `json:"filter"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+func newSegmentResponse(segment *model.Segment) segmentResponse {
+	return segmentResponse{
+		ID:        segment.ID,
+		Name:      segment.Name,
+		Filter:    newSegmentFilter(segment.Filter),
+		CreatedAt: segment.CreatedAt,
+	}
+}
+
+func (app *application) addSegment(c echo.Context) error {
+	var req segmentRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	filter, err := req.Filter.parse()
+	if err != nil {
+		return app.validationError(err)
+	}
+	segment := &model.Segment{
+		Name:   req.Name,
+		Filter: filter,
+	}
+	if err := app.validateSegment(c, segment); err != nil {
+		return err
+	}
+
+	if err := app.store.AddSegment(c.Request().Context(), segment); err != nil {
+		app.logger.WriteError("Error adding segment", err, map[string]interface{}{
+			"name": segment.Name,
+		})
+		return app.segmentError(err, "Failed to add segment")
+	}
+
+	return c.JSON(http.StatusCreated, newSegmentResponse(segment))
+}
+
+func (app *application) getSegments(c echo.Context) error {
+	segments, err := app.store.GetSegments(c.Request().Context())
+	if err != nil {
+		app.logger.WriteError("Error getting segments", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get segments",
+		}
+	}
+
+	resp := make([]segmentResponse, len(segments))
+	for i, segment := range segments {
+		resp[i] = newSegmentResponse(segment)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (app *application) getSegmentByID(c echo.Context) error {
+	segment, err := app.segment(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newSegmentResponse(segment))
+}
+
+// updateSegmentRequest renames the segment or replaces its filter as a whole.
+type updateSegmentRequest struct {
+	Name   *string        `json:"name"`
+	Filter *segmentFilter `json:"filter"`
+}
+
+func (app *application) updateSegment(c echo.Context) error {
+	segment, err := app.segment(c)
+	if err != nil {
+		return err
+	}
+
+	var req updateSegmentRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error binding request", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Failed to bind request",
+		}
+	}
+
+	if req.Name != nil {
+		segment.Name = *req.Name
+	}
+	if req.Filter != nil {
+		filter, err := req.Filter.parse()
+		if err != nil {
+			return app.validationError(err)
+		}
+		segment.Filter = filter
+	}
+	if err := app.validateSegment(c, segment); err != nil {
+		return err
+	}
+
+	if err := app.store.UpdateSegment(c.Request().Context(), segment); err != nil {
+		app.logger.WriteError("Error updating segment", err, map[string]interface{}{
+			"id": segment.ID,
+		})
+		return app.segmentError(err, "Failed to update segment")
+	}
+
+	return c.JSON(http.StatusOK, newSegmentResponse(segment))
+}
+
+func (app *application) deleteSegment(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid segment ID",
+		}
+	}
+
+	if err := app.store.DeleteSegment(c.Request().Context(), id); err != nil {
+		app.logger.WriteError("Error deleting segment", err, map[string]interface{}{
+			"id": id,
+		})
+		return app.segmentError(err, "Failed to delete segment")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getSegmentMembers lists the current members matching the segment's filter, a
+// page at a time like the member listing.
+func (app *application) getSegmentMembers(c echo.Context) error {
+	segment, err := app.segment(c)
+	if err != nil {
+		return err
+	}
+
+	listOpts, err := listOptions(c)
+	if err != nil {
+		return err
+	}
+
+	return app.listMembers(c, model.MemberListOptions{
+		ListOptions: listOpts,
+		Segment:     &segment.Filter,
+	})
+}
+
+// validateSegment validates the segment against the custom field definitions and
+// checks that the sport it selects exists.
+func (app *application) validateSegment(c echo.Context, segment *model.Segment) error {
+	fields, err := app.customFields(c)
+	if err != nil {
+		return err
+	}
+	if err := segment.Validate(fields); err != nil {
+		return app.validationError(err)
+	}
+
+	if segment.Filter.SportID == nil {
+		return nil
+	}
+	if _, err := app.store.GetSportByID(c.Request().Context(), *segment.Filter.SportID); err != nil {
+		app.logger.WriteError("Error getting sport", err, map[string]interface{}{
+			"id": *segment.Filter.SportID,
+		})
+		switch {
+		case errors.Is(err, postgres.ErrSportNotFound):
+			var verr model.ValidationError
+			verr.Add("filter.sport_id", model.CodeInvalidValue, "Sport not found")
+			return app.validationError(&verr)
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get sport",
+			}
+		}
+	}
+	return nil
+}
+
+// segment loads the segment addressed by the :id path parameter.
+func (app *application) segment(c echo.Context) (*model.Segment, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid segment ID",
+		}
+	}
+
+	segment, err := app.store.GetSegmentByID(c.Request().Context(), id)
+	if err != nil {
+		app.logger.WriteError("Error getting segment", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, app.segmentError(err, "Failed to get segment")
+	}
+
+	return segment, nil
+}
+
+// segmentError maps the errors of the segment store to responses, with msg for
+// unexpected errors.
+func (app *application) segmentError(err error, msg string) error {
+	switch {
+	case errors.Is(err, postgres.ErrSegmentNotFound):
+		return &echo.HTTPError{
+			Code:    http.StatusNotFound,
+			Message: "Segment not found",
+		}
+	case errors.Is(err, postgres.ErrSegmentAlreadyExists):
+		return &echo.HTTPError{
+			Code:    http.StatusConflict,
+			Message: "Segment already exists",
+		}
+	case errors.Is(err, postgres.ErrMissingRequiredField):
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Missing required fields",
+		}
+	default:
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: msg,
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/postgres"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// registerSensitiveRoutes registers the emergency contacts and medical notes of
+// members. They are kept out of the member response, need their own token, and
+// every access is recorded in the sensitive access log.
+func (app *application) registerSensitiveRoutes(e *echo.Group) {
+	auth := requireToken(app.sensitive.token, "Sensitive data")
+	e.GET("/members/:id/emergency-contacts", app.getEmergencyContacts, auth)
+	e.POST("/members/:id/emergency-contacts", app.addEmergencyContact, auth)
+	e.DELETE("/members/:id/emergency-contacts/:contact_id", app.deleteEmergencyContact, auth)
+	e.GET("/members/:id/medical-notes", app.getMedicalNotes, auth)
+	e.POST("/members/:id/medical-notes", app.addMedicalNote, auth)
+	e.DELETE("/members/:id/medical-notes/:note_id", app.deleteMedicalNote, auth)
+}
+
+type emergencyContactRequest struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	PhoneNumber  string `json:"phone_number"`
+}
+
+type emergencyContactResponse struct {
+	ID           uuid.UUID `json:"id"`
+	MemberID     uuid.UUID `json:"member_id"`
+	Name         string    `json:"name"`
+	Relationship string    `json:"relationship"`
+	PhoneNumber  string    `json:"phone_number"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newEmergencyContactResponse(contact *model.EmergencyContact) emergencyContactResponse {
+	return emergencyContactResponse{
+		ID:           contact.ID,
+		MemberID:     contact.MemberID,
+		Name:         contact.Name,
+		Relationship: contact.Relationship,
+		PhoneNumber:  contact.PhoneNumber,
+		CreatedAt:    contact.CreatedAt,
+	}
+}
+
+type medicalNoteRequest struct {
+	Category model.MedicalCategory `json:"category"`
+	Note     string                `json:"note"`
+}
+
+type medicalNoteResponse struct {
+	ID        uuid.UUID             `json:"id"`
+	MemberID  uuid.UUID             `json:"member_id"`
+	Category  model.MedicalCategory `json:"category"`
+	Note      string                `json:"note"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+func newMedicalNoteResponse(note *model.MedicalNote) medicalNoteResponse {
+	return medicalNoteResponse{
+		ID:        note.ID,
+		MemberID:  note.MemberID,
+		Category:  note.Category,
+		Note:      note.Note,
+		CreatedAt: note.CreatedAt,
+	}
+}
+
+func (app *application) getEmergencyContacts(c echo.Context) error {
+	memberID, err := app.sensitiveAccess(c, model.SensitiveResourceEmergencyContacts, model.SensitiveActionRead)
+	if err != nil {
+		return err
+	}
+
+	contacts, err := app.store.GetEmergencyContacts(c.Request().Context(), memberID)
+	if err != nil {
+		app.logger.WriteError("Error getting emergency contacts", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get emergency contacts",
+		}
+	}
+
+	contactsResponse := make([]emergencyContactResponse, len(contacts))
+	for i, contact := range contacts {
+		contactsResponse[i] = newEmergencyContactResponse(contact)
+	}
+
+	return c.JSON(http.StatusOK, contactsResponse)
+}
+
+func (app *application) addEmergencyContact(c echo.Context) error {
+	var req emergencyContactRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	contact := &model.EmergencyContact{
+		Name:         req.Name,
+		Relationship: req.Relationship,
+		PhoneNumber:  model.NormalizePhone(req.PhoneNumber, app.members.phoneRegion),
+	}
+	if err := contact.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	memberID, err := app.sensitiveAccess(c, model.SensitiveResourceEmergencyContacts, model.SensitiveActionCreate)
+	if err != nil {
+		return err
+	}
+	contact.MemberID = memberID
+
+	if err := app.store.AddEmergencyContact(c.Request().Context(), contact); err != nil {
+		app.logger.WriteError("Error adding emergency contact", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to add emergency contact",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newEmergencyContactResponse(contact))
+}
+
+func (app *application) deleteEmergencyContact(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("contact_id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid emergency contact ID",
+		}
+	}
+
+	memberID, err := app.sensitiveAccess(c, model.SensitiveResourceEmergencyContacts, model.SensitiveActionDelete)
+	if err != nil {
+		return err
+	}
+
+	if err := app.store.DeleteEmergencyContact(c.Request().Context(), memberID, id); err != nil {
+		app.logger.WriteError("Error deleting emergency contact", err, map[string]interface{}{
+			"member_id": memberID,
+			"id":        id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrEmergencyContactNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Emergency contact not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete emergency contact",
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (app *application) getMedicalNotes(c echo.Context) error {
+	memberID, err := app.sensitiveAccess(c, model.SensitiveResourceMedicalNotes, model.SensitiveActionRead)
+	if err != nil {
+		return err
+	}
+
+	notes, err := app.store.GetMedicalNotes(c.Request().Context(), memberID)
+	if err != nil {
+		app.logger.WriteError("Error getting medical notes", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get medical notes",
+		}
+	}
+
+	notesResponse := make([]medicalNoteResponse, len(notes))
+	for i, note := range notes {
+		notesResponse[i] = newMedicalNoteResponse(note)
+	}
+
+	return c.JSON(http.StatusOK, notesResponse)
+}
+
+func (app *application) addMedicalNote(c echo.Context) error {
+	var req medicalNoteRequest
+	if err := c.Bind(&req); err != nil {
+		app.logger.WriteError("Error parsing the request body", err, nil)
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body",
+		}
+	}
+
+	note := &model.MedicalNote{
+		Category: req.Category,
+		Note:     req.Note,
+	}
+	if err := note.Validate(); err != nil {
+		return app.validationError(err)
+	}
+
+	memberID, err := app.sensitiveAccess(c, model.SensitiveResourceMedicalNotes, model.SensitiveActionCreate)
+	if err != nil {
+		return err
+	}
+	note.MemberID = memberID
+
+	if err := app.store.AddMedicalNote(c.Request().Context(), note); err != nil {
+		app.logger.WriteError("Error adding medical note", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMemberNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to add medical note",
+			}
+		}
+	}
+
+	return c.JSON(http.StatusCreated, newMedicalNoteResponse(note))
+}
+
+func (app *application) deleteMedicalNote(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("note_id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid medical note ID",
+		}
+	}
+
+	memberID, err := app.sensitiveAccess(c, model.SensitiveResourceMedicalNotes, model.SensitiveActionDelete)
+	if err != nil {
+		return err
+	}
+
+	if err := app.store.DeleteMedicalNote(c.Request().Context(), memberID, id); err != nil {
+		app.logger.WriteError("Error deleting medical note", err, map[string]interface{}{
+			"member_id": memberID,
+			"id":        id,
+		})
+
+		switch {
+		case errors.Is(err, postgres.ErrMedicalNoteNotFound):
+			return &echo.HTTPError{
+				Code:    http.StatusNotFound,
+				Message: "Medical note not found",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete medical note",
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// sensitiveAccess records the access to the sensitive data of the member addressed
+// by the :id path parameter and returns the member's ID. Nothing is served when the
+// access cannot be recorded.
+func (app *application) sensitiveAccess(c echo.Context, resource model.SensitiveResource, action model.SensitiveAction) (uuid.UUID, error) {
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	access := &model.SensitiveAccess{
+		MemberID: memberID,
+		Actor:    actor(c),
+		Resource: resource,
+		Action:   action,
+	}
+	if err := app.store.LogSensitiveAccess(c.Request().Context(), access); err != nil {
+		app.logger.WriteError("Error logging sensitive access", err, map[string]interface{}{
+			"member_id": memberID,
+			"resource":  resource,
+			"action":    action,
+		})
+		return uuid.Nil, &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to record access",
+		}
+	}
+
+	return memberID, nil
+}
+
+type sensitiveAccessResponse struct {
+	ID         int64                   `json:"id"`
+	MemberID   uuid.UUID               `json:"member_id"`
+	Actor      string                  `json:"actor"`
+	Resource   model.SensitiveResource `json:"resource"`
+	Action     model.SensitiveAction   `json:"action"`
+	AccessedAt time.Time               `json:"accessed_at"`
+}
+
+// getSensitiveAccessLog lists who accessed the member's sensitive data, latest first.
+func (app *application) getSensitiveAccessLog(c echo.Context) error {
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return &echo.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid member ID",
+		}
+	}
+
+	log, err := app.store.GetSensitiveAccessLog(c.Request().Context(), memberID)
+	if err != nil {
+		app.logger.WriteError("Error getting sensitive access log", err, map[string]interface{}{
+			"member_id": memberID,
+		})
+		return &echo.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get sensitive access log",
+		}
+	}
+
+	logResponse := make([]sensitiveAccessResponse, len(log))
+	for i, access := range log {
+		logResponse[i] = sensitiveAccessResponse{
+			ID:         access.ID,
+			MemberID:   access.MemberID,
+			Actor:      access.Actor,
+			Resource:   access.Resource,
+			Action:     access.Action,
+			AccessedAt: access.AccessedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, logResponse)
+}
@@ -33,30 +33,13 @@ func (app *application) addSport(c echo.Context) error {
 		}
 	}
 
-	if req.Name == "" {
-		app.logger.WriteError("Required fields missing", nil, map[string]interface{}{
-			"name": req.Name,
-		})
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Required fields missing",
-		}
-	}
-
 	sport := &model.Sport{
 		Name:        req.Name,
 		Description: req.Description,
 	}
 
-	if !sport.Valid() {
-		app.logger.WriteError("Invalid sport", nil, map[string]interface{}{
-			"name":        req.Name,
-			"description": req.Description,
-		})
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid sport",
-		}
+	if err := sport.Validate(); err != nil {
+		return app.validationError(err)
 	}
 
 	if err := app.store.AddSport(c.Request().Context(), sport); err != nil {
@@ -126,13 +109,31 @@ func (app *application) getSportByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, sportResponse)
 }
 
+type listSportsResponse struct {
+	Sports     []getSportResponse `json:"sports"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
 func (app *application) getAllSports(c echo.Context) error {
-	sports, err := app.store.GetAllSports(c.Request().Context())
+	opts, err := listOptions(c)
+	if err != nil {
+		return err
+	}
+
+	sports, next, err := app.store.ListSports(c.Request().Context(), opts)
 	if err != nil {
 		app.logger.WriteError("Error getting sports", err, nil)
-		return &echo.HTTPError{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to get sports",
+		switch {
+		case errors.Is(err, model.ErrInvalidCursor):
+			return &echo.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid cursor",
+			}
+		default:
+			return &echo.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get sports",
+			}
 		}
 	}
 	sportsResponse := make([]getSportResponse, len(sports))
@@ -143,7 +144,11 @@ func (app *application) getAllSports(c echo.Context) error {
 			Description: sport.Description,
 		}
 	}
-	return c.JSON(http.StatusOK, sportsResponse)
+	resp := listSportsResponse{Sports: sportsResponse}
+	if next != nil {
+		resp.NextCursor = next.String()
+	}
+	return c.JSON(http.StatusOK, resp)
 }
 
 func (app *application) deleteSport(c echo.Context) error {
@@ -231,15 +236,8 @@ func (app *application) updateSport(c echo.Context) error {
 		sport.Description = *req.Description
 	}
 
-	if !sport.Valid() {
-		app.logger.WriteError("Invalid sport", nil, map[string]interface{}{
-			"name":        req.Name,
-			"description": req.Description,
-		})
-		return &echo.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid sport",
-		}
+	if err := sport.Validate(); err != nil {
+		return app.validationError(err)
 	}
 
 	if err := app.store.UpdateSport(c.Request().Context(), sport); err != nil {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"time"
 
 	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
 	"github.com/google/uuid"
@@ -11,25 +12,138 @@ type memberStore interface {
 	AddMember(ctx context.Context, member *model.Member) error
 	GetMemberByID(ctx context.Context, id uuid.UUID) (*model.Member, error)
 	GetMemberByEmail(ctx context.Context, email string) (*model.Member, error)
-	GetAllMembers(ctx context.Context) ([]*model.Member, error)
-	UpdateMember(ctx context.Context, member *model.Member) error
+	GetMemberByPhone(ctx context.Context, phone string) (*model.Member, error)
+	GetMemberPhones(ctx context.Context) (map[uuid.UUID]string, error)
+	UpdateMemberPhones(ctx context.Context, phones map[uuid.UUID]string) (int64, error)
+	ListMembers(ctx context.Context, opts model.MemberListOptions) ([]*model.Member, *model.Cursor, error)
+	SearchMembers(ctx context.Context, query string, limit int) ([]*model.MemberMatch, error)
+	GetMemberAnniversaries(ctx context.Context, month time.Month, year int) ([]*model.Member, error)
+	UpdateMember(ctx context.Context, member *model.Member, change *model.MemberStatusChange) error
+	GetMemberStatusHistory(ctx context.Context, memberID uuid.UUID) ([]*model.MemberStatusChange, error)
 	DeleteMember(ctx context.Context, id uuid.UUID) error
+	RestoreMember(ctx context.Context, id uuid.UUID) (*model.Member, error)
+	RequestEmailChange(ctx context.Context, change *model.EmailChange) error
+	ConfirmEmailChange(ctx context.Context, change *model.EmailChange) (*model.Member, error)
+	FindDuplicateMembers(ctx context.Context, minSimilarity float64, limit int) ([]*model.DuplicateMatch, error)
+	MergeMembers(ctx context.Context, merge *model.MemberMerge) error
+	GetMemberMerges(ctx context.Context, memberID uuid.UUID) ([]*model.MemberMerge, error)
+	GetMemberTags(ctx context.Context, memberID uuid.UUID) ([]string, error)
+	AddMemberTags(ctx context.Context, memberID uuid.UUID, tags []string) error
+	RemoveMemberTags(ctx context.Context, memberID uuid.UUID, tags []string) error
+	GetMemberCredits(ctx context.Context, memberID uuid.UUID) ([]*model.MemberCredit, error)
+	SetMemberPhoto(ctx context.Context, id uuid.UUID, updatedAt *time.Time) error
+	PurgeMembers(ctx context.Context, archivedBefore time.Time) ([]uuid.UUID, error)
 }
 
 type sportStore interface {
 	AddSport(ctx context.Context, sport *model.Sport) error
 	GetSportByID(ctx context.Context, id uuid.UUID) (*model.Sport, error)
-	GetAllSports(ctx context.Context) ([]*model.Sport, error)
+	ListSports(ctx context.Context, opts model.ListOptions) ([]*model.Sport, *model.Cursor, error)
 	UpdateSport(ctx context.Context, sport *model.Sport) error
 	DeleteSport(ctx context.Context, id uuid.UUID) error
 }
 
 type membershipStore interface {
-	AddMembership(ctx context.Context, membership *model.Membership) error
+	AddMembership(ctx context.Context, membership *model.Membership, actor string) error
+	GetMembershipByID(ctx context.Context, id uuid.UUID) (*model.Membership, error)
+	GetAllMemberships(ctx context.Context) ([]*model.Membership, error)
+	GetMembershipsByMemberID(ctx context.Context, memberID uuid.UUID) ([]*model.Membership, error)
+	UpdateMembership(ctx context.Context, membership *model.Membership) error
+	TransitionMembership(ctx context.Context, membership *model.Membership, transition *model.MembershipTransition) error
+	TransitionOverdueMemberships(ctx context.Context, from, to model.MembershipStatus, dueBefore time.Time, actor, reason string) (int64, error)
+	GetMembershipHistory(ctx context.Context, membershipID uuid.UUID) ([]*model.MembershipTransition, error)
+	GetRenewableMemberships(ctx context.Context, now time.Time) ([]*model.Membership, error)
+	RenewMembership(ctx context.Context, current, next *model.Membership, actor string) error
+	ChangeMembership(ctx context.Context, current, next *model.Membership, transition *model.MembershipTransition, credit *model.MemberCredit) error
+	GetOutstandingDues(ctx context.Context, sportID *uuid.UUID) ([]*model.Due, error)
+}
+
+type paymentStore interface {
+	AddPayment(ctx context.Context, payment *model.Payment) error
+	GetPaymentByID(ctx context.Context, id uuid.UUID) (*model.Payment, error)
+	GetPaymentsByMembershipID(ctx context.Context, membershipID uuid.UUID) ([]*model.Payment, error)
+}
+
+type planStore interface {
+	AddPlan(ctx context.Context, plan *model.Plan) error
+	GetPlanByID(ctx context.Context, id uuid.UUID) (*model.Plan, error)
+	GetPlansBySportID(ctx context.Context, sportID uuid.UUID) ([]*model.Plan, error)
+	UpdatePlan(ctx context.Context, plan *model.Plan) error
+	DeletePlan(ctx context.Context, id uuid.UUID) error
+}
+
+type invoiceStore interface {
+	AddInvoice(ctx context.Context, invoice *model.Invoice) error
+	GetInvoiceByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error)
+	GetInvoicesByMemberID(ctx context.Context, memberID uuid.UUID) ([]*model.Invoice, error)
+	IssueInvoice(ctx context.Context, invoice *model.Invoice, prefix string, now time.Time) error
+	UpdateInvoiceStatus(ctx context.Context, invoice *model.Invoice, status model.InvoiceStatus) error
+}
+
+type freezeStore interface {
+	GetFreezesByMembershipID(ctx context.Context, membershipID uuid.UUID) ([]*model.Freeze, error)
+	FreezeMembership(ctx context.Context, membership *model.Membership, freeze *model.Freeze, transition *model.MembershipTransition) error
+	UnfreezeMembership(ctx context.Context, membership *model.Membership, freeze *model.Freeze, transition *model.MembershipTransition) error
+}
+
+type householdStore interface {
+	AddHousehold(ctx context.Context, household *model.Household) error
+	GetHouseholdByID(ctx context.Context, id uuid.UUID) (*model.Household, error)
+	GetHouseholdByMemberID(ctx context.Context, memberID uuid.UUID) (*model.Household, error)
+	DeleteHousehold(ctx context.Context, id uuid.UUID) error
+	AddHouseholdMember(ctx context.Context, member *model.HouseholdMember) error
+	RemoveHouseholdMember(ctx context.Context, householdID, memberID uuid.UUID) error
+	SetHouseholdPrimary(ctx context.Context, householdID, memberID uuid.UUID) error
+	GetHouseholdMemberships(ctx context.Context, householdID uuid.UUID) ([]*model.Membership, error)
+	GetHouseholdDues(ctx context.Context, householdID uuid.UUID) ([]*model.Due, error)
+}
+
+type sensitiveStore interface {
+	AddEmergencyContact(ctx context.Context, contact *model.EmergencyContact) error
+	GetEmergencyContacts(ctx context.Context, memberID uuid.UUID) ([]*model.EmergencyContact, error)
+	DeleteEmergencyContact(ctx context.Context, memberID, id uuid.UUID) error
+	AddMedicalNote(ctx context.Context, note *model.MedicalNote) error
+	GetMedicalNotes(ctx context.Context, memberID uuid.UUID) ([]*model.MedicalNote, error)
+	DeleteMedicalNote(ctx context.Context, memberID, id uuid.UUID) error
+	LogSensitiveAccess(ctx context.Context, access *model.SensitiveAccess) error
+	GetSensitiveAccessLog(ctx context.Context, memberID uuid.UUID) ([]*model.SensitiveAccess, error)
+}
+
+type customFieldStore interface {
+	AddCustomField(ctx context.Context, field *model.CustomField) error
+	GetCustomFieldByID(ctx context.Context, id uuid.UUID) (*model.CustomField, error)
+	GetCustomFields(ctx context.Context) ([]*model.CustomField, error)
+	UpdateCustomField(ctx context.Context, field *model.CustomField) error
+	DeleteCustomField(ctx context.Context, id uuid.UUID) error
+}
+
+type segmentStore interface {
+	AddSegment(ctx context.Context, segment *model.Segment) error
+	GetSegmentByID(ctx context.Context, id uuid.UUID) (*model.Segment, error)
+	GetSegments(ctx context.Context) ([]*model.Segment, error)
+	UpdateSegment(ctx context.Context, segment *model.Segment) error
+	DeleteSegment(ctx context.Context, id uuid.UUID) error
+}
+
+type batchStore interface {
+	AddBatch(ctx context.Context, batch *model.Batch) error
+	GetBatchByID(ctx context.Context, id uuid.UUID) (*model.Batch, error)
+	GetBatchesBySportID(ctx context.Context, sportID uuid.UUID) ([]*model.Batch, error)
+	UpdateBatch(ctx context.Context, batch *model.Batch) error
+	DeleteBatch(ctx context.Context, id uuid.UUID) error
 }
 
 type store interface {
 	memberStore
 	sportStore
 	membershipStore
+	paymentStore
+	planStore
+	invoiceStore
+	freezeStore
+	householdStore
+	sensitiveStore
+	customFieldStore
+	segmentStore
+	batchStore
 }
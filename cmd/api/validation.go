@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/labstack/echo/v4"
+)
+
+type fieldErrorResponse struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type validationErrorResponse struct {
+	Message string               `json:"message"`
+	Errors  []fieldErrorResponse `json:"errors"`
+}
+
+// validationError converts an error returned by an entity's Validate method into
+// a 422 response listing each invalid field, so clients can point at the inputs.
+func (app *application) validationError(err error) error {
+	app.logger.WriteError("Validation failed", err, nil)
+
+	resp := validationErrorResponse{
+		Message: "Validation failed",
+		Errors:  []fieldErrorResponse{},
+	}
+	var verr *model.ValidationError
+	if errors.As(err, &verr) {
+		for _, f := range verr.Fields {
+			resp.Errors = append(resp.Errors, fieldErrorResponse{
+				Field:   f.Field,
+				Code:    f.Code,
+				Message: f.Message,
+			})
+		}
+	}
+
+	return &echo.HTTPError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: resp,
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+)
+
+// workerActor is recorded as the actor of the status changes made by the membership worker.
+const workerActor = "system"
+
+// runMembershipWorker moves overdue memberships through the grace period to
+// expiry and renews the auto-renewing ones every worker interval until ctx is cancelled.
+func (app *application) runMembershipWorker(ctx context.Context) {
+	app.logger.WriteInfo("Membership worker started", map[string]interface{}{
+		"interval":     app.worker.interval.String(),
+		"grace_period": app.worker.gracePeriod.String(),
+	})
+
+	ticker := time.NewTicker(app.worker.interval)
+	defer ticker.Stop()
+
+	for {
+		app.processMemberships(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			app.logger.WriteInfo("Membership worker stopped", nil)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (app *application) processMemberships(ctx context.Context, now time.Time) {
+	memberships, err := app.store.GetRenewableMemberships(ctx, now)
+	if err != nil {
+		app.logger.WriteError("Error getting renewable memberships", err, nil)
+	}
+
+	for _, membership := range memberships {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var plan *model.Plan
+		if membership.PlanID != nil {
+			if plan, err = app.store.GetPlanByID(ctx, *membership.PlanID); err != nil {
+				app.logger.WriteError("Error getting plan of renewed membership", err, map[string]interface{}{
+					"id":      membership.ID,
+					"plan_id": *membership.PlanID,
+				})
+				continue
+			}
+		}
+
+		next := membership.NextPeriod(plan)
+		if err := app.store.RenewMembership(ctx, membership, next, workerActor); err != nil {
+			app.logger.WriteError("Error renewing membership", err, map[string]interface{}{
+				"id": membership.ID,
+			})
+			continue
+		}
+
+		app.logger.WriteInfo("Membership renewed", map[string]interface{}{
+			"id":       membership.ID,
+			"renewal":  next.ID,
+			"due_date": next.DueDate,
+		})
+	}
+
+	expired, err := app.store.TransitionOverdueMemberships(ctx, model.MembershipGrace, model.MembershipExpired, now.Add(-app.worker.gracePeriod), workerActor, "grace period ended")
+	if err != nil {
+		app.logger.WriteError("Error expiring memberships", err, nil)
+	} else if expired > 0 {
+		app.logger.WriteInfo("Memberships expired", map[string]interface{}{
+			"count": expired,
+		})
+	}
+
+	overdue, err := app.store.TransitionOverdueMemberships(ctx, model.MembershipActive, model.MembershipGrace, now, workerActor, "due date passed")
+	if err != nil {
+		app.logger.WriteError("Error moving memberships to grace", err, nil)
+	} else if overdue > 0 {
+		app.logger.WriteInfo("Memberships entered grace period", map[string]interface{}{
+			"count": overdue,
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package blob stores files such as member photos outside the database.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps blobs under slash separated keys, e.g. "members/<id>/photo.jpg".
+type Store interface {
+	// Put writes the blob, replacing any blob with the same key.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get returns the blob, or ErrNotFound. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
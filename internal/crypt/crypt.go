@@ -0,0 +1,74 @@
+// Package crypt encrypts sensitive values before they are stored, using
+// AES-256-GCM with a random nonce per value, and signs tokens handed to members.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the length of an AES-256 key in bytes.
+const KeySize = 32
+
+var (
+	ErrInvalidKey = errors.New("invalid encryption key")
+	ErrDecrypt    = errors.New("failed to decrypt value")
+)
+
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// ParseKey decodes a base64 encoded key of KeySize bytes.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidKey, len(key), KeySize)
+	}
+	return key, nil
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidKey, len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt seals the plaintext, bound to the additional data, and returns the
+// nonce followed by the ciphertext. The same additional data must be given to
+// Decrypt, so a value cannot be moved to another row unnoticed.
+func (c *Cipher) Encrypt(plaintext string, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(plaintext), additionalData), nil
+}
+
+func (c *Cipher) Decrypt(ciphertext []byte, additionalData []byte) (string, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, sealed := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS memberships_status_due_date_idx;
+DROP INDEX IF EXISTS memberships_active_member_sport_type_idx;
+ALTER TABLE memberships ADD UNIQUE (member_id, sport_id, type);
+ALTER TABLE memberships DROP COLUMN IF EXISTS auto_renew;
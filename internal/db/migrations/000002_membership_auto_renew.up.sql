@@ -0,0 +1,7 @@
+ALTER TABLE memberships ADD COLUMN auto_renew BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Renewals create a new row per period, so only one active membership per member, sport and type is unique.
+ALTER TABLE memberships DROP CONSTRAINT memberships_member_id_sport_id_type_key;
+CREATE UNIQUE INDEX memberships_active_member_sport_type_idx ON memberships (member_id, sport_id, type) WHERE status = 1;
+
+CREATE INDEX memberships_status_due_date_idx ON memberships (status, due_date);
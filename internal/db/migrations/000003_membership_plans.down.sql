@@ -0,0 +1,2 @@
+ALTER TABLE memberships DROP COLUMN IF EXISTS plan_id;
+DROP TABLE IF EXISTS plans;
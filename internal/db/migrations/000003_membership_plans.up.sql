@@ -0,0 +1,12 @@
+CREATE TABLE plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sport_id INTEGER NOT NULL REFERENCES sports(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    billing_period TEXT NOT NULL CHECK(billing_period IN ('monthly', 'quarterly', 'annual')),
+    price NUMERIC(10, 2) NOT NULL CHECK(price > 0),
+    type TEXT NOT NULL CHECK(type IN ('membership', 'training'))
+);
+
+ALTER TABLE plans ADD UNIQUE (sport_id, name);
+
+ALTER TABLE memberships ADD COLUMN plan_id UUID REFERENCES plans(id) ON DELETE SET NULL;
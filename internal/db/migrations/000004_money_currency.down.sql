@@ -0,0 +1,11 @@
+ALTER TABLE plans
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN price TYPE NUMERIC(10, 2);
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN amount TYPE NUMERIC(10, 2);
+
+ALTER TABLE memberships
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN fees TYPE NUMERIC(10, 2);
@@ -0,0 +1,12 @@
+-- Amounts are stored with three decimals so every ISO 4217 currency round-trips exactly.
+ALTER TABLE memberships
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'INR' CHECK(currency ~ '^[A-Z]{3}$'),
+    ALTER COLUMN fees TYPE NUMERIC(12, 3);
+
+ALTER TABLE payments
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'INR' CHECK(currency ~ '^[A-Z]{3}$'),
+    ALTER COLUMN amount TYPE NUMERIC(12, 3);
+
+ALTER TABLE plans
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'INR' CHECK(currency ~ '^[A-Z]{3}$'),
+    ALTER COLUMN price TYPE NUMERIC(12, 3);
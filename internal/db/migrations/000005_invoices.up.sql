@@ -0,0 +1,29 @@
+-- Invoice numbers are allocated from this table inside the issuing transaction so that they are gap-free.
+CREATE TABLE invoice_sequences (
+    prefix TEXT NOT NULL,
+    year INTEGER NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (prefix, year)
+);
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    number TEXT UNIQUE,
+    member_id UUID NOT NULL REFERENCES members(id),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'paid', 'void')),
+    currency TEXT NOT NULL CHECK(currency ~ '^[A-Z]{3}$'),
+    total NUMERIC(12, 3) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    issued_at TIMESTAMPTZ
+);
+
+CREATE INDEX invoices_member_id_idx ON invoices (member_id);
+
+CREATE TABLE invoice_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    membership_id UUID REFERENCES memberships(id) ON DELETE SET NULL,
+    description TEXT NOT NULL,
+    currency TEXT NOT NULL CHECK(currency ~ '^[A-Z]{3}$'),
+    amount NUMERIC(12, 3) NOT NULL
+);
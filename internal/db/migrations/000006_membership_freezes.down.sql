@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS membership_freezes;
+ALTER TABLE plans
+    DROP COLUMN IF EXISTS max_freeze_days,
+    DROP COLUMN IF EXISTS min_freeze_days;
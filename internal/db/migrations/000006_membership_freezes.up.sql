@@ -0,0 +1,15 @@
+ALTER TABLE plans
+    ADD COLUMN max_freeze_days INTEGER NOT NULL DEFAULT 0 CHECK(max_freeze_days >= 0),
+    ADD COLUMN min_freeze_days INTEGER NOT NULL DEFAULT 0 CHECK(min_freeze_days >= 0);
+
+CREATE TABLE membership_freezes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    membership_id UUID NOT NULL REFERENCES memberships(id) ON DELETE CASCADE,
+    start_date TIMESTAMPTZ NOT NULL,
+    end_date TIMESTAMPTZ,
+    days INTEGER NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT ''
+);
+
+-- A membership can only have one freeze in progress.
+CREATE UNIQUE INDEX membership_freezes_open_idx ON membership_freezes (membership_id) WHERE end_date IS NULL;
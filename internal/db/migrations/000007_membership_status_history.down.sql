@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS memberships_current_member_sport_type_idx;
+CREATE UNIQUE INDEX memberships_active_member_sport_type_idx ON memberships (member_id, sport_id, type) WHERE status = 1;
+DROP TABLE IF EXISTS membership_status_history;
@@ -0,0 +1,15 @@
+CREATE TABLE membership_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    membership_id UUID NOT NULL REFERENCES memberships(id) ON DELETE CASCADE,
+    from_status INTEGER,
+    to_status INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX membership_status_history_membership_id_idx ON membership_status_history (membership_id, changed_at);
+
+-- Pending, active, frozen and grace memberships are all current, so a member can only hold one of each per sport and type.
+DROP INDEX IF EXISTS memberships_active_member_sport_type_idx;
+CREATE UNIQUE INDEX memberships_current_member_sport_type_idx ON memberships (member_id, sport_id, type) WHERE status IN (1, 2, 3, 4);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS sports_created_at_id_idx;
+DROP INDEX IF EXISTS members_created_at_id_idx;
+DROP INDEX IF EXISTS members_name_id_idx;
+ALTER TABLE sports DROP COLUMN IF EXISTS created_at;
+ALTER TABLE members DROP COLUMN IF EXISTS created_at;
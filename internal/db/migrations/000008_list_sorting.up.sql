@@ -0,0 +1,7 @@
+ALTER TABLE members ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE sports ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Listings are ordered by the sort key with the ID as tie breaker.
+CREATE INDEX members_name_id_idx ON members (name, id);
+CREATE INDEX members_created_at_id_idx ON members (created_at, id);
+CREATE INDEX sports_created_at_id_idx ON sports (created_at, id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS members_address_trgm_idx;
+DROP INDEX IF EXISTS members_phone_trgm_idx;
+DROP INDEX IF EXISTS members_email_trgm_idx;
+DROP INDEX IF EXISTS members_name_trgm_idx;
+DROP EXTENSION IF EXISTS pg_trgm;
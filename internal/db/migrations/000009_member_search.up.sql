@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes back the typo-tolerant member search.
+CREATE INDEX members_name_trgm_idx ON members USING GIN (name gin_trgm_ops);
+CREATE INDEX members_email_trgm_idx ON members USING GIN (email gin_trgm_ops);
+CREATE INDEX members_phone_trgm_idx ON members USING GIN (phone gin_trgm_ops);
+CREATE INDEX members_address_trgm_idx ON members USING GIN (address gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS members_join_month_idx;
+ALTER TABLE members DROP COLUMN IF EXISTS join_date;
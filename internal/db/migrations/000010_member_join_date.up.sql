@@ -0,0 +1,6 @@
+ALTER TABLE members ADD COLUMN join_date DATE NOT NULL DEFAULT CURRENT_DATE;
+
+-- Existing members joined no later than their record was created.
+UPDATE members SET join_date = created_at::DATE;
+
+CREATE INDEX members_join_month_idx ON members ((EXTRACT(MONTH FROM join_date)));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS members_deleted_at_idx;
+ALTER TABLE members DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE members ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX members_deleted_at_idx ON members (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE households (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- A member belongs to at most one household.
+CREATE TABLE household_members (
+    household_id UUID NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    member_id UUID NOT NULL UNIQUE REFERENCES members(id) ON DELETE CASCADE,
+    relationship TEXT NOT NULL CHECK(relationship IN ('parent', 'child', 'spouse')),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (household_id, member_id)
+);
+
+-- The primary payer receives the household's bills; there is only one.
+CREATE UNIQUE INDEX household_members_primary_idx ON household_members (household_id) WHERE is_primary;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sensitive_access_log;
+DROP TABLE IF EXISTS member_medical_notes;
+DROP TABLE IF EXISTS member_emergency_contacts;
@@ -0,0 +1,33 @@
+-- The name, phone and note columns hold values encrypted by the application.
+CREATE TABLE member_emergency_contacts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    member_id UUID NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    name BYTEA NOT NULL,
+    relationship TEXT NOT NULL,
+    phone BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX member_emergency_contacts_member_id_idx ON member_emergency_contacts (member_id);
+
+CREATE TABLE member_medical_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    member_id UUID NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    category TEXT NOT NULL CHECK(category IN ('allergy', 'condition', 'medication', 'other')),
+    note BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX member_medical_notes_member_id_idx ON member_medical_notes (member_id);
+
+-- Every access to the tables above. There is no foreign key so the log outlives purged members.
+CREATE TABLE sensitive_access_log (
+    id BIGSERIAL PRIMARY KEY,
+    member_id UUID NOT NULL,
+    actor TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    action TEXT NOT NULL,
+    accessed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX sensitive_access_log_member_id_idx ON sensitive_access_log (member_id, accessed_at);
@@ -0,0 +1,2 @@
+-- The photo itself is kept in blob storage; this records when it was last replaced.
+ALTER TABLE members ADD COLUMN photo_updated_at TIMESTAMPTZ;
@@ -0,0 +1,17 @@
+-- Audit of merged duplicate members. The duplicate's details are copied as it may
+-- be purged later; there are no foreign keys for the same reason.
+CREATE TABLE member_merges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    survivor_id UUID NOT NULL,
+    duplicate_id UUID NOT NULL,
+    duplicate_name TEXT NOT NULL,
+    duplicate_email TEXT NOT NULL,
+    duplicate_phone TEXT NOT NULL,
+    membership_ids UUID[] NOT NULL,
+    invoice_ids UUID[] NOT NULL,
+    actor TEXT NOT NULL,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX member_merges_survivor_id_idx ON member_merges (survivor_id);
+CREATE INDEX member_merges_duplicate_id_idx ON member_merges (duplicate_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS member_email_changes;
+DROP INDEX IF EXISTS members_email_lower_idx;
+ALTER TABLE members ADD CONSTRAINT members_email_key UNIQUE (email);
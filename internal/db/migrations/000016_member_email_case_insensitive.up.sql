@@ -0,0 +1,34 @@
+-- Emails that differ only in case belong to the same person. Refuse to migrate
+-- while current members share an email that way and list them; they have to be
+-- merged (POST /members/merge) or given another email first.
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('%s: %s', email, ids), '; ' ORDER BY email)
+    INTO conflicts
+    FROM (
+        SELECT lower(email) AS email, string_agg(id::TEXT, ', ' ORDER BY created_at) AS ids
+        FROM members
+        WHERE deleted_at IS NULL
+        GROUP BY lower(email)
+        HAVING count(*) > 1
+    ) c;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'members share an email ignoring case: %', conflicts;
+    END IF;
+END $$;
+
+-- The address keeps the case it was typed in, but is unique ignoring case among
+-- current members, so a merged duplicate may keep its address while archived.
+ALTER TABLE members DROP CONSTRAINT members_email_key;
+CREATE UNIQUE INDEX members_email_lower_idx ON members (lower(email)) WHERE deleted_at IS NULL;
+
+-- A new email awaits confirmation here until the member confirms it; one per member.
+CREATE TABLE member_email_changes (
+    member_id UUID PRIMARY KEY REFERENCES members(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,14 @@
+-- Extra member data each club defines for itself, e.g. school name or belt rank.
+-- Allowed values, when given, restrict a text field to those values.
+CREATE TABLE custom_fields (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL CHECK(type IN ('text', 'number', 'boolean', 'date')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The values of a member's custom fields, by field name.
+ALTER TABLE members ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX members_custom_fields_idx ON members USING GIN (custom_fields jsonb_path_ops);
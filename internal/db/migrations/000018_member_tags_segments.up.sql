@@ -0,0 +1,16 @@
+-- Free-form labels grouping members, e.g. "competition squad". Tags are kept in lower case.
+CREATE TABLE member_tags (
+    member_id UUID NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (member_id, tag)
+);
+CREATE INDEX member_tags_tag_idx ON member_tags (tag);
+
+-- A saved selection of members; the filter is evaluated whenever the segment is listed.
+CREATE TABLE segments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
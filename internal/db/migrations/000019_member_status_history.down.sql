@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS member_status_history;
+
+-- The suspended and banned statuses do not exist before this migration.
+UPDATE members SET status = 0 WHERE status > 1;
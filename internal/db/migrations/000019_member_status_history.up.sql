@@ -0,0 +1,13 @@
+-- Every change of a member's status, with the reason code and who made it.
+CREATE TABLE member_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    member_id UUID NOT NULL REFERENCES members(id) ON DELETE CASCADE,
+    from_status INTEGER NOT NULL,
+    to_status INTEGER NOT NULL,
+    reason TEXT NOT NULL CHECK(reason IN ('moved_away', 'non_payment', 'medical', 'disciplinary')),
+    note TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX member_status_history_member_id_idx ON member_status_history (member_id, changed_at);
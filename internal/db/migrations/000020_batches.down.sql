@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS memberships_batch_id_idx;
+ALTER TABLE memberships DROP COLUMN IF EXISTS batch_id;
+DROP TABLE IF EXISTS batch_slots;
+DROP TABLE IF EXISTS batches;
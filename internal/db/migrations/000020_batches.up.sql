@@ -0,0 +1,26 @@
+-- A sport runs several batches, e.g. "U-12 Cricket Mon/Wed 5pm", each taking a
+-- limited number of members.
+CREATE TABLE batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sport_id INTEGER NOT NULL REFERENCES sports(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    capacity INTEGER NOT NULL CHECK(capacity > 0),
+    min_age INTEGER CHECK(min_age >= 0),
+    max_age INTEGER CHECK(max_age >= min_age),
+    level TEXT NOT NULL CHECK(level IN ('beginner', 'intermediate', 'advanced')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (sport_id, name)
+);
+
+-- The weekly schedule of a batch; weekday 0 is Sunday.
+CREATE TABLE batch_slots (
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK(weekday BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK(end_time > start_time),
+    PRIMARY KEY (batch_id, weekday, start_time)
+);
+
+-- A batch cannot be deleted while memberships refer to it.
+ALTER TABLE memberships ADD COLUMN batch_id UUID REFERENCES batches(id);
+CREATE INDEX memberships_batch_id_idx ON memberships (batch_id) WHERE batch_id IS NOT NULL;
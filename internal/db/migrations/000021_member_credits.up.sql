@@ -0,0 +1,15 @@
+-- The credit members hold with the club, e.g. the unused value of a membership
+-- changed to a cheaper plan. Positive rows add to the balance of their currency
+-- and negative rows draw from it for the invoice they are applied to.
+CREATE TABLE member_credits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    member_id UUID NOT NULL REFERENCES members(id),
+    currency TEXT NOT NULL CHECK(currency ~ '^[A-Z]{3}$'),
+    amount NUMERIC(12, 3) NOT NULL CHECK(amount <> 0),
+    membership_id UUID REFERENCES memberships(id) ON DELETE SET NULL,
+    invoice_id UUID REFERENCES invoices(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX member_credits_member_id_idx ON member_credits (member_id);
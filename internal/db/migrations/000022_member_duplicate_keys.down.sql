@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS members_email_key_idx;
+DROP INDEX IF EXISTS members_phone_key_idx;
+
+ALTER TABLE members
+    DROP COLUMN IF EXISTS email_key,
+    DROP COLUMN IF EXISTS phone_key;
@@ -0,0 +1,9 @@
+-- Keys that likely duplicates share, indexed so that finding duplicates does not
+-- compare every pair of members: the last ten digits of the phone number and the
+-- email local part without its +tag.
+ALTER TABLE members
+    ADD COLUMN phone_key TEXT GENERATED ALWAYS AS (right(regexp_replace(phone, '\D', '', 'g'), 10)) STORED,
+    ADD COLUMN email_key TEXT GENERATED ALWAYS AS (lower(regexp_replace(split_part(email, '@', 1), '\+.*$', ''))) STORED;
+
+CREATE INDEX members_phone_key_idx ON members (phone_key) WHERE deleted_at IS NULL;
+CREATE INDEX members_email_key_idx ON members (email_key) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+-- Changes already recorded with the new reasons are kept.
+ALTER TABLE member_status_history
+    DROP CONSTRAINT IF EXISTS member_status_history_reason_check,
+    ADD CONSTRAINT member_status_history_reason_check
+        CHECK(reason IN ('moved_away', 'non_payment', 'medical', 'disciplinary')) NOT VALID;
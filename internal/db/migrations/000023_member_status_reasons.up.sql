@@ -0,0 +1,5 @@
+-- Reinstating a member, and changes no other code fits, get their own reasons.
+ALTER TABLE member_status_history
+    DROP CONSTRAINT member_status_history_reason_check,
+    ADD CONSTRAINT member_status_history_reason_check
+        CHECK(reason IN ('moved_away', 'non_payment', 'medical', 'disciplinary', 'reinstated', 'other'));
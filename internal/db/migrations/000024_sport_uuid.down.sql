@@ -0,0 +1,36 @@
+ALTER TABLE sports ADD COLUMN serial_id SERIAL;
+
+ALTER TABLE memberships ADD COLUMN sport_serial_id INTEGER;
+UPDATE memberships ms SET sport_serial_id = s.serial_id FROM sports s WHERE s.id = ms.sport_id;
+ALTER TABLE plans ADD COLUMN sport_serial_id INTEGER;
+UPDATE plans p SET sport_serial_id = s.serial_id FROM sports s WHERE s.id = p.sport_id;
+ALTER TABLE batches ADD COLUMN sport_serial_id INTEGER;
+UPDATE batches b SET sport_serial_id = s.serial_id FROM sports s WHERE s.id = b.sport_id;
+
+ALTER TABLE memberships DROP COLUMN sport_id;
+ALTER TABLE plans DROP COLUMN sport_id;
+ALTER TABLE batches DROP COLUMN sport_id;
+ALTER TABLE sports DROP COLUMN id;
+
+ALTER TABLE sports RENAME COLUMN serial_id TO id;
+ALTER SEQUENCE sports_serial_id_seq RENAME TO sports_id_seq;
+ALTER TABLE sports ADD PRIMARY KEY (id);
+CREATE INDEX sports_created_at_id_idx ON sports (created_at, id);
+
+ALTER TABLE memberships RENAME COLUMN sport_serial_id TO sport_id;
+ALTER TABLE memberships
+    ALTER COLUMN sport_id SET NOT NULL,
+    ADD FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX memberships_current_member_sport_type_idx ON memberships (member_id, sport_id, type) WHERE status IN (1, 2, 3, 4);
+
+ALTER TABLE plans RENAME COLUMN sport_serial_id TO sport_id;
+ALTER TABLE plans
+    ALTER COLUMN sport_id SET NOT NULL,
+    ADD FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE,
+    ADD UNIQUE (sport_id, name);
+
+ALTER TABLE batches RENAME COLUMN sport_serial_id TO sport_id;
+ALTER TABLE batches
+    ALTER COLUMN sport_id SET NOT NULL,
+    ADD FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE,
+    ADD UNIQUE (sport_id, name);
@@ -0,0 +1,38 @@
+-- Sports are keyed by UUID like every other table. The integer keys, and the
+-- columns referring to them, are replaced by UUIDs keeping the same references.
+ALTER TABLE sports ADD COLUMN uuid UUID NOT NULL DEFAULT uuid_generate_v4();
+
+ALTER TABLE memberships ADD COLUMN sport_uuid UUID;
+UPDATE memberships ms SET sport_uuid = s.uuid FROM sports s WHERE s.id = ms.sport_id;
+ALTER TABLE plans ADD COLUMN sport_uuid UUID;
+UPDATE plans p SET sport_uuid = s.uuid FROM sports s WHERE s.id = p.sport_id;
+ALTER TABLE batches ADD COLUMN sport_uuid UUID;
+UPDATE batches b SET sport_uuid = s.uuid FROM sports s WHERE s.id = b.sport_id;
+
+-- Dropping the columns also drops their foreign keys and indexes.
+ALTER TABLE memberships DROP COLUMN sport_id;
+ALTER TABLE plans DROP COLUMN sport_id;
+ALTER TABLE batches DROP COLUMN sport_id;
+ALTER TABLE sports DROP COLUMN id;
+
+ALTER TABLE sports RENAME COLUMN uuid TO id;
+ALTER TABLE sports ADD PRIMARY KEY (id);
+CREATE INDEX sports_created_at_id_idx ON sports (created_at, id);
+
+ALTER TABLE memberships RENAME COLUMN sport_uuid TO sport_id;
+ALTER TABLE memberships
+    ALTER COLUMN sport_id SET NOT NULL,
+    ADD FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX memberships_current_member_sport_type_idx ON memberships (member_id, sport_id, type) WHERE status IN (1, 2, 3, 4);
+
+ALTER TABLE plans RENAME COLUMN sport_uuid TO sport_id;
+ALTER TABLE plans
+    ALTER COLUMN sport_id SET NOT NULL,
+    ADD FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE,
+    ADD UNIQUE (sport_id, name);
+
+ALTER TABLE batches RENAME COLUMN sport_uuid TO sport_id;
+ALTER TABLE batches
+    ALTER COLUMN sport_id SET NOT NULL,
+    ADD FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE,
+    ADD UNIQUE (sport_id, name);
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BatchLevel string
+
+var (
+	BatchLevelBeginner     BatchLevel = "beginner"
+	BatchLevelIntermediate BatchLevel = "intermediate"
+	BatchLevelAdvanced     BatchLevel = "advanced"
+)
+
+// MaxBatchAge is the highest age a batch's age range may go up to.
+const MaxBatchAge = 120
+
+// Batch is a group of a sport training on a weekly schedule, e.g. "U-12 Cricket
+// Mon/Wed 5pm". Memberships enrol in a batch until it reaches its capacity. The
+// age range is for information only, as members have no date of birth.
+type Batch struct {
+	ID        uuid.UUID   `db:"id"`
+	SportID   uuid.UUID   `db:"sport_id"`
+	Name      string      `db:"name"`
+	Capacity  int         `db:"capacity"`
+	MinAge    *int        `db:"min_age"`
+	MaxAge    *int        `db:"max_age"`
+	Level     BatchLevel  `db:"level"`
+	CreatedAt time.Time   `db:"created_at"`
+	Slots     []BatchSlot // Ordered by weekday, Sunday first, then start time.
+	Enrolled  int         // The number of current memberships in the batch.
+}
+
+// BatchSlot is a weekly training session of a batch.
+type BatchSlot struct {
+	Weekday time.Weekday `db:"weekday"`
+	Start   TimeOfDay    `db:"start_time"`
+	End     TimeOfDay    `db:"end_time"`
+}
+
+// Full reports whether the batch has no places left.
+func (b *Batch) Full() bool {
+	return b.Enrolled >= b.Capacity
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the batch, or nil.
+func (b *Batch) Validate() error {
+	var verr ValidationError
+
+	switch {
+	case b.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case len(b.Name) <= 2:
+		verr.Add("name", CodeTooShort, "Name must be at least 3 characters")
+	}
+
+	if b.Capacity <= 0 {
+		verr.Add("capacity", CodeOutOfRange, "Capacity must be positive")
+	}
+
+	if b.MinAge != nil && (*b.MinAge < 0 || *b.MinAge > MaxBatchAge) {
+		verr.Add("min_age", CodeOutOfRange, fmt.Sprintf("Minimum age must be between 0 and %d", MaxBatchAge))
+	}
+	if b.MaxAge != nil && (*b.MaxAge < 0 || *b.MaxAge > MaxBatchAge) {
+		verr.Add("max_age", CodeOutOfRange, fmt.Sprintf("Maximum age must be between 0 and %d", MaxBatchAge))
+	}
+	if b.MinAge != nil && b.MaxAge != nil && *b.MinAge > *b.MaxAge {
+		verr.Add("max_age", CodeOutOfRange, "Maximum age must not be below the minimum age")
+	}
+
+	switch b.Level {
+	case BatchLevelBeginner, BatchLevelIntermediate, BatchLevelAdvanced:
+	case "":
+		verr.Add("level", CodeRequired, "Level is required")
+	default:
+		verr.Add("level", CodeInvalidValue, "Level must be beginner, intermediate or advanced")
+	}
+
+	validateBatchSlots(&verr, b.Slots)
+
+	return verr.Err()
+}
+
+// validateBatchSlots records in verr the first problem with the slots, if any.
+func validateBatchSlots(verr *ValidationError, slots []BatchSlot) {
+	if len(slots) == 0 {
+		verr.Add("slots", CodeRequired, "At least one weekly slot is required")
+		return
+	}
+
+	slots = slices.Clone(slots)
+	SortBatchSlots(slots)
+	for i, slot := range slots {
+		switch {
+		case slot.Weekday < time.Sunday || slot.Weekday > time.Saturday:
+			verr.Add("slots", CodeInvalidValue, "Weekday is not a day of the week")
+			return
+		case slot.Start < 0 || slot.End >= 24*60 || slot.End <= slot.Start:
+			verr.Add("slots", CodeOutOfRange, "Slots must end after they start, on the same day")
+			return
+		case i > 0 && slots[i-1].Weekday == slot.Weekday && slots[i-1].End > slot.Start:
+			verr.Add("slots", CodeOutOfRange, fmt.Sprintf("Slots overlap on %s", slot.Weekday))
+			return
+		}
+	}
+}
+
+// SortBatchSlots orders the slots by weekday, Sunday first, then start time.
+func SortBatchSlots(slots []BatchSlot) {
+	slices.SortFunc(slots, func(a, b BatchSlot) int {
+		if a.Weekday != b.Weekday {
+			return int(a.Weekday - b.Weekday)
+		}
+		return int(a.Start - b.Start)
+	})
+}
+
+// ParseWeekday returns the weekday with the given English name, ignoring case,
+// e.g. "monday".
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+var ErrInvalidTimeOfDay = errors.New("invalid time of day, expected HH:MM")
+
+// TimeOfDay is a time on the clock, in minutes since midnight.
+type TimeOfDay int
+
+// ParseTimeOfDay parses a 24-hour time of the form "17:30".
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, ErrInvalidTimeOfDay
+	}
+	return TimeOfDay(t.Hour()*60 + t.Minute()), nil
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	parsed, err := ParseTimeOfDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
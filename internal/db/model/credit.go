@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberCredit is an entry in the credit ledger of a member. Credit is granted
+// with a positive amount and drawn by an invoice with a negative one.
+type MemberCredit struct {
+	ID           uuid.UUID  `db:"id"`
+	MemberID     uuid.UUID  `db:"member_id"`
+	Amount       Money      `db:"amount"`
+	MembershipID *uuid.UUID `db:"membership_id"`
+	InvoiceID    *uuid.UUID `db:"invoice_id"`
+	Reason       string     `db:"reason"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// CreditBalances returns the balance of the credit entries in each currency,
+// leaving out the currencies with nothing left.
+func CreditBalances(credits []*MemberCredit) []Money {
+	var balances []Money
+	for _, credit := range credits {
+		i := 0
+		for i < len(balances) && !balances[i].SameCurrency(credit.Amount) {
+			i++
+		}
+		if i == len(balances) {
+			balances = append(balances, Money{Currency: credit.Amount.Currency})
+		}
+		balances[i] = balances[i].Add(credit.Amount)
+	}
+
+	nonZero := balances[:0]
+	for _, balance := range balances {
+		if !balance.IsZero() {
+			nonZero = append(nonZero, balance)
+		}
+	}
+	return nonZero
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CustomFieldType string
+
+var (
+	CustomFieldText    CustomFieldType = "text"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldBoolean CustomFieldType = "boolean"
+	CustomFieldDate    CustomFieldType = "date"
+)
+
+// CustomFieldDateLayout is the format of the values of date fields.
+const CustomFieldDateLayout = time.DateOnly
+
+var ErrInvalidCustomFieldValue = errors.New("invalid custom field value")
+
+// CustomField is an extra field on members defined by the club. Values are kept
+// on the member by the field's name. A text field with AllowedValues only takes
+// one of them.
+type CustomField struct {
+	ID            uuid.UUID       `db:"id"`
+	Name          string          `db:"name"`
+	Type          CustomFieldType `db:"type"`
+	Required      bool            `db:"required"`
+	AllowedValues []string        `db:"allowed_values"`
+	CreatedAt     time.Time       `db:"created_at"`
+}
+
+var customFieldNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// Validate returns a *ValidationError listing the invalid fields of the custom field definition, or nil.
+func (f *CustomField) Validate() error {
+	var verr ValidationError
+
+	switch {
+	case f.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case !customFieldNameRegexp.MatchString(f.Name):
+		verr.Add("name", CodeInvalidFormat, "Name must be lowercase letters, digits and underscores, starting with a letter")
+	}
+
+	switch f.Type {
+	case CustomFieldText, CustomFieldNumber, CustomFieldBoolean, CustomFieldDate:
+	case "":
+		verr.Add("type", CodeRequired, "Type is required")
+	default:
+		verr.Add("type", CodeInvalidValue, "Type must be text, number, boolean or date")
+	}
+
+	switch {
+	case len(f.AllowedValues) > 0 && f.Type != CustomFieldText:
+		verr.Add("allowed_values", CodeInvalidValue, "Allowed values are only supported on text fields")
+	case slices.Contains(f.AllowedValues, ""):
+		verr.Add("allowed_values", CodeInvalidValue, "Allowed values must not be empty")
+	case len(slices.Compact(slices.Sorted(slices.Values(f.AllowedValues)))) != len(f.AllowedValues):
+		verr.Add("allowed_values", CodeInvalidValue, "Allowed values must be unique")
+	}
+
+	return verr.Err()
+}
+
+// ParseValue converts the text form of a value of the field, as given in a query
+// string, to the value stored on members.
+func (f *CustomField) ParseValue(s string) (any, error) {
+	var value any = s
+	switch f.Type {
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidCustomFieldValue, s)
+		}
+		value = n
+	case CustomFieldBoolean:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a boolean", ErrInvalidCustomFieldValue, s)
+		}
+		value = b
+	}
+	if msg := f.check(value); msg != "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCustomFieldValue, msg)
+	}
+	return value, nil
+}
+
+// check returns why value is not a valid value of the field, or "" if it is.
+// Values are in their JSON form, so numbers are float64.
+func (f *CustomField) check(value any) string {
+	switch f.Type {
+	case CustomFieldText:
+		s, ok := value.(string)
+		switch {
+		case !ok:
+			return "must be text"
+		case len(f.AllowedValues) > 0 && !slices.Contains(f.AllowedValues, s):
+			return "must be one of " + strings.Join(f.AllowedValues, ", ")
+		}
+	case CustomFieldNumber:
+		if _, ok := value.(float64); !ok {
+			return "must be a number"
+		}
+	case CustomFieldBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be true or false"
+		}
+	case CustomFieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a date"
+		}
+		if _, err := time.Parse(CustomFieldDateLayout, s); err != nil {
+			return "must be a date in the form YYYY-MM-DD"
+		}
+	}
+	return ""
+}
+
+// validateCustomFields records in verr the values that do not match their
+// definition in fields, the values of unknown fields and the missing required ones.
+func validateCustomFields(verr *ValidationError, values map[string]any, fields []*CustomField) {
+	for _, f := range fields {
+		if value, ok := values[f.Name]; f.Required && (!ok || value == nil) {
+			verr.Add("custom_fields."+f.Name, CodeRequired, fmt.Sprintf("%s is required", f.Name))
+		}
+	}
+	validateCustomFieldValues(verr, "custom_fields.", values, fields)
+}
+
+// validateCustomFieldValues records in verr, under prefix and the field name, the
+// values that do not match their definition in fields and those of unknown fields.
+// Nil values are skipped.
+func validateCustomFieldValues(verr *ValidationError, prefix string, values map[string]any, fields []*CustomField) {
+	names := slices.Sorted(maps.Keys(values))
+	for _, name := range names {
+		value := values[name]
+		i := slices.IndexFunc(fields, func(f *CustomField) bool { return f.Name == name })
+		switch {
+		case i < 0:
+			verr.Add(prefix+name, CodeInvalidValue, fmt.Sprintf("%s is not a custom field", name))
+		case value == nil:
+		default:
+			if msg := fields[i].check(value); msg != "" {
+				verr.Add(prefix+name, CodeInvalidValue, fmt.Sprintf("%s %s", name, msg))
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AgingBucket string
+
+var (
+	AgingBucketCurrent AgingBucket = "current"
+	AgingBucket0To30   AgingBucket = "0-30"
+	AgingBucket31To60  AgingBucket = "31-60"
+	AgingBucket61To90  AgingBucket = "61-90"
+	AgingBucketOver90  AgingBucket = "90+"
+	AgingBuckets                   = []AgingBucket{AgingBucketCurrent, AgingBucket0To30, AgingBucket31To60, AgingBucket61To90, AgingBucketOver90}
+)
+
+// Due is the amount still owed on a membership.
+type Due struct {
+	MemberID     uuid.UUID `db:"member_id"`
+	MemberName   string    `db:"member_name"`
+	Email        string    `db:"email"`
+	PhoneNumber  string    `db:"phone"`
+	MembershipID uuid.UUID `db:"membership_id"`
+	SportID      uuid.UUID `db:"sport_id"`
+	SportName    string    `db:"sport_name"`
+	DueDate      time.Time `db:"due_date"`
+	Fee          Money     `db:"fees"`
+	Paid         Money     `db:"paid"`
+}
+
+func (d *Due) Outstanding() Money {
+	return d.Fee.Sub(d.Paid)
+}
+
+// DaysOverdue returns the number of whole days since the due date, or 0 if it is not yet due.
+func (d *Due) DaysOverdue(now time.Time) int {
+	if !now.After(d.DueDate) {
+		return 0
+	}
+	return int(now.Sub(d.DueDate).Hours() / 24)
+}
+
+// AgingBucket returns the bucket of the due by days overdue. Dues not yet past
+// their due date are current.
+func (d *Due) AgingBucket(now time.Time) AgingBucket {
+	switch days := d.DaysOverdue(now); {
+	case !now.After(d.DueDate):
+		return AgingBucketCurrent
+	case days <= 30:
+		return AgingBucket0To30
+	case days <= 60:
+		return AgingBucket31To60
+	case days <= 90:
+		return AgingBucket61To90
+	default:
+		return AgingBucketOver90
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueAgingBucket(t *testing.T) {
+	due := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantDays int
+		want     AgingBucket
+	}{
+		{"before due date", due.AddDate(0, 0, -5), 0, AgingBucketCurrent},
+		{"on due date", due, 0, AgingBucketCurrent},
+		{"hours overdue", due.Add(6 * time.Hour), 0, AgingBucket0To30},
+		{"30 days overdue", due.AddDate(0, 0, 30), 30, AgingBucket0To30},
+		{"31 days overdue", due.AddDate(0, 0, 31), 31, AgingBucket31To60},
+		{"60 days overdue", due.AddDate(0, 0, 60), 60, AgingBucket31To60},
+		{"90 days overdue", due.AddDate(0, 0, 90), 90, AgingBucket61To90},
+		{"91 days overdue", due.AddDate(0, 0, 91), 91, AgingBucketOver90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Due{DueDate: due}
+			if got := d.DaysOverdue(tt.now); got != tt.wantDays {
+				t.Errorf("DaysOverdue = %d, want %d", got, tt.wantDays)
+			}
+			if got := d.AgingBucket(tt.now); got != tt.want {
+				t.Errorf("AgingBucket = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
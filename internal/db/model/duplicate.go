@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultDuplicateNameSimilarity is the trigram similarity from which two names
+// are taken to belong to the same person.
+const DefaultDuplicateNameSimilarity = 0.6
+
+type DuplicateReason string
+
+var (
+	DuplicateReasonPhone DuplicateReason = "phone"
+	DuplicateReasonEmail DuplicateReason = "email"
+	DuplicateReasonName  DuplicateReason = "name"
+)
+
+// DuplicateMatch is a pair of members that are likely the same person, with the
+// reasons they were matched on. Phones match on their last ten digits and emails
+// on their local part, ignoring case and any "+tag".
+type DuplicateMatch struct {
+	Member         *Member
+	Duplicate      *Member
+	Reasons        []DuplicateReason
+	NameSimilarity float64
+}
+
+// MemberMerge is the audit record of merging a duplicate member into the survivor.
+// The duplicate's memberships, with their payments, and draft invoices are moved
+// to the survivor and the duplicate is archived.
+type MemberMerge struct {
+	ID             uuid.UUID   `db:"id"`
+	SurvivorID     uuid.UUID   `db:"survivor_id"`
+	DuplicateID    uuid.UUID   `db:"duplicate_id"`
+	DuplicateName  string      `db:"duplicate_name"`
+	DuplicateEmail string      `db:"duplicate_email"`
+	DuplicatePhone string      `db:"duplicate_phone"`
+	MembershipIDs  []uuid.UUID `db:"membership_ids"`
+	InvoiceIDs     []uuid.UUID `db:"invoice_ids"`
+	Actor          string      `db:"actor"`
+	MergedAt       time.Time   `db:"merged_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChange is a new email address of a member awaiting confirmation. The
+// member keeps their current address until the new one is confirmed.
+type EmailChange struct {
+	MemberID    uuid.UUID `db:"member_id"`
+	Email       string    `db:"email"`
+	RequestedAt time.Time `db:"requested_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+func (e *EmailChange) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
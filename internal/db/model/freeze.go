@@ -0,0 +1,74 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFreezeNotAllowed   = errors.New("freeze not allowed")
+	ErrFreezeLimitReached = errors.New("yearly freeze limit reached")
+)
+
+// Freeze is a period during which a membership is paused. The due date of the
+// membership is extended by the frozen days when the freeze ends.
+type Freeze struct {
+	ID           uuid.UUID  `db:"id"`
+	MembershipID uuid.UUID  `db:"membership_id"`
+	StartDate    time.Time  `db:"start_date"`
+	EndDate      *time.Time `db:"end_date"`
+	Days         int        `db:"days"`
+	Reason       string     `db:"reason"`
+}
+
+func (f *Freeze) Open() bool {
+	return f.EndDate == nil
+}
+
+// FreezeDays returns the number of whole days between start and end.
+func FreezeDays(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// FrozenDaysInYear returns the days credited to the closed freezes that started in the given year.
+func FrozenDaysInYear(freezes []*Freeze, year int) int {
+	var days int
+	for _, freeze := range freezes {
+		if !freeze.Open() && freeze.StartDate.Year() == year {
+			days += freeze.Days
+		}
+	}
+	return days
+}
+
+// CanFreeze checks whether a membership of the plan may start a freeze, given the
+// days already frozen this year. A nil plan has no limits.
+func (p *Plan) CanFreeze(usedDays int) error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxFreezeDays == 0 {
+		return ErrFreezeNotAllowed
+	}
+	if usedDays+p.MinFreezeDays > p.MaxFreezeDays || usedDays >= p.MaxFreezeDays {
+		return fmt.Errorf("%w: %d of %d days used", ErrFreezeLimitReached, usedDays, p.MaxFreezeDays)
+	}
+	return nil
+}
+
+// FreezeCredit returns the number of days the due date is extended by, and that
+// count towards the yearly allowance, for a freeze of the given length, given the
+// days already frozen this year. A freeze ended before the plan minimum counts as
+// the minimum, and the credit is capped at the yearly allowance.
+func (p *Plan) FreezeCredit(days, usedDays int) int {
+	if p == nil {
+		return days
+	}
+	return min(max(days, p.MinFreezeDays), max(p.MaxFreezeDays-usedDays, 0))
+}
@@ -0,0 +1,30 @@
+package model
+
+import "testing"
+
+func TestPlanFreezeCredit(t *testing.T) {
+	plan := &Plan{MinFreezeDays: 7, MaxFreezeDays: 30}
+	tests := []struct {
+		name     string
+		plan     *Plan
+		days     int
+		usedDays int
+		want     int
+	}{
+		{"within limits", plan, 10, 0, 10},
+		{"minimum", plan, 7, 0, 7},
+		{"ended early", plan, 2, 0, 7},
+		{"ended on the day", plan, 0, 0, 7},
+		{"capped at allowance", plan, 20, 15, 15},
+		{"ended early near allowance", plan, 2, 25, 5},
+		{"allowance used", plan, 10, 30, 0},
+		{"no plan", nil, 2, 100, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plan.FreezeCredit(tt.days, tt.usedDays); got != tt.want {
+				t.Errorf("FreezeCredit(%d, %d) = %d, want %d", tt.days, tt.usedDays, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Relationship string
+
+var (
+	RelationshipParent Relationship = "parent"
+	RelationshipChild  Relationship = "child"
+	RelationshipSpouse Relationship = "spouse"
+)
+
+// Household groups the members of a family. Its bills go to the primary payer.
+type Household struct {
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	Members   []*HouseholdMember
+}
+
+type HouseholdMember struct {
+	HouseholdID  uuid.UUID    `db:"household_id"`
+	MemberID     uuid.UUID    `db:"member_id"`
+	Name         string       `db:"name"`
+	Relationship Relationship `db:"relationship"`
+	Primary      bool         `db:"is_primary"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the household, or nil.
+func (h *Household) Validate() error {
+	var verr ValidationError
+
+	switch {
+	case h.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case len(h.Name) <= 2:
+		verr.Add("name", CodeTooShort, "Name must be at least 3 characters")
+	}
+
+	return verr.Err()
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the household member, or nil.
+func (m *HouseholdMember) Validate() error {
+	var verr ValidationError
+
+	if m.MemberID == uuid.Nil {
+		verr.Add("member_id", CodeRequired, "Member ID is required")
+	}
+
+	switch m.Relationship {
+	case RelationshipParent, RelationshipChild, RelationshipSpouse:
+	case "":
+		verr.Add("relationship", CodeRequired, "Relationship is required")
+	default:
+		verr.Add("relationship", CodeInvalidValue, "Relationship must be parent, child or spouse")
+	}
+
+	return verr.Err()
+}
+
+// Member returns the household member with the given member ID, or nil. A nil
+// household has no members.
+func (h *Household) Member(memberID uuid.UUID) *HouseholdMember {
+	if h == nil {
+		return nil
+	}
+	for _, m := range h.Members {
+		if m.MemberID == memberID {
+			return m
+		}
+	}
+	return nil
+}
+
+// Payer returns the primary payer of the household, or nil if it has none.
+func (h *Household) Payer() *HouseholdMember {
+	if h == nil {
+		return nil
+	}
+	for _, m := range h.Members {
+		if m.Primary {
+			return m
+		}
+	}
+	return nil
+}
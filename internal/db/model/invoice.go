@@ -0,0 +1,101 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvoiceEmpty = errors.New("invoice has no line items")
+
+type InvoiceStatus string
+
+var (
+	InvoiceStatusDraft  InvoiceStatus = "draft"
+	InvoiceStatusIssued InvoiceStatus = "issued"
+	InvoiceStatusPaid   InvoiceStatus = "paid"
+	InvoiceStatusVoid   InvoiceStatus = "void"
+)
+
+// invoiceTransitions lists the statuses an invoice may move to from each status.
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusDraft:  {InvoiceStatusIssued, InvoiceStatusVoid},
+	InvoiceStatusIssued: {InvoiceStatusPaid, InvoiceStatusVoid},
+}
+
+type Invoice struct {
+	ID        uuid.UUID     `db:"id"`
+	Number    string        `db:"number"`
+	MemberID  uuid.UUID     `db:"member_id"`
+	Status    InvoiceStatus `db:"status"`
+	Total     Money         `db:"total"`
+	CreatedAt time.Time     `db:"created_at"`
+	IssuedAt  *time.Time    `db:"issued_at"`
+	Items     []*InvoiceItem
+}
+
+type InvoiceItem struct {
+	ID           uuid.UUID  `db:"id"`
+	InvoiceID    uuid.UUID  `db:"invoice_id"`
+	MembershipID *uuid.UUID `db:"membership_id"`
+	Description  string     `db:"description"`
+	Amount       Money      `db:"amount"`
+}
+
+// NewInvoice returns a draft invoice for the member whose total is the sum of the
+// items. All items must be in the same currency.
+func NewInvoice(memberID uuid.UUID, items []*InvoiceItem) (*Invoice, error) {
+	if len(items) == 0 {
+		return nil, ErrInvoiceEmpty
+	}
+
+	total := Money{Currency: items[0].Amount.Currency}
+	for _, item := range items {
+		if !item.Amount.SameCurrency(total) {
+			return nil, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, total.Currency, item.Amount.Currency)
+		}
+		total = total.Add(item.Amount)
+	}
+
+	return &Invoice{
+		MemberID: memberID,
+		Status:   InvoiceStatusDraft,
+		Total:    total,
+		Items:    items,
+	}, nil
+}
+
+// ApplyCredit draws up to the total of the invoice from the member's credit
+// balance, adding a line item for it, and returns the amount drawn. Nothing is
+// drawn from a balance in another currency.
+func (i *Invoice) ApplyCredit(balance Money) Money {
+	applied := Money{Currency: i.Total.Currency}
+	if !balance.SameCurrency(i.Total) || !balance.IsPositive() || !i.Total.IsPositive() {
+		return applied
+	}
+
+	applied.Amount = min(balance.Amount, i.Total.Amount)
+	i.Items = append(i.Items, &InvoiceItem{
+		Description: "Account credit",
+		Amount:      Money{Amount: -applied.Amount, Currency: applied.Currency},
+	})
+	i.Total = i.Total.Sub(applied)
+	return applied
+}
+
+// CanTransition reports whether the invoice may move from its current status to status.
+func (i *Invoice) CanTransition(status InvoiceStatus) bool {
+	for _, next := range invoiceTransitions[i.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// InvoiceNumber formats the sequence number of an invoice issued in year, e.g. INV-2026-000123.
+func InvoiceNumber(prefix string, year, sequence int) string {
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, sequence)
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+type SortField string
+
+var (
+	SortName    SortField = "name"
+	SortCreated SortField = "created"
+)
+
+type SortOrder string
+
+var (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// ListOptions selects one page of a listing. After is the cursor returned with
+// the previous page, or nil for the first page.
+type ListOptions struct {
+	Sort  SortField
+	Order SortOrder
+	Limit int
+	After *Cursor
+}
+
+// Cursor marks the last item of a page by its sort key and ID. It is only valid
+// for the sort and order it was created with.
+type Cursor struct {
+	Sort  SortField `json:"s"`
+	Order SortOrder `json:"o"`
+	Key   string    `json:"k"`
+	ID    uuid.UUID `json:"i"`
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c *Cursor) String() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseCursor decodes a token returned by Cursor.String.
+func ParseCursor(token string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
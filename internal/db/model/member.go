@@ -2,6 +2,7 @@ package model
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,11 +12,15 @@ type MemberStatus int
 const (
 	MemberStatusInactive MemberStatus = iota
 	MemberStatusActive
+	MemberStatusSuspended
+	MemberStatusBanned
 )
 
 var MemberStatusMap = map[MemberStatus]string{
-	MemberStatusInactive: "Inactive",
-	MemberStatusActive:   "Active",
+	MemberStatusInactive:  "Inactive",
+	MemberStatusActive:    "Active",
+	MemberStatusSuspended: "Suspended",
+	MemberStatusBanned:    "Banned",
 }
 
 type Member struct {
@@ -24,26 +29,92 @@ type Member struct {
 	Email       string       `db:"email"`
 	PhoneNumber string       `db:"phone"`
 	Address     string       `db:"address"`
+	JoinDate    time.Time    `db:"join_date"`
 	Status      MemberStatus `db:"status"`
+	CreatedAt   time.Time    `db:"created_at"`
+	DeletedAt   *time.Time   `db:"deleted_at"`
+	// PhotoUpdatedAt is when the member's photo was last uploaded, or nil if they have none.
+	PhotoUpdatedAt *time.Time `db:"photo_updated_at"`
+	// CustomFields holds the values of the club's custom fields by field name.
+	CustomFields map[string]any `db:"custom_fields"`
 }
 
-// TODO: Return which fields are invalid
-func (m *Member) Valid() bool {
-	if len(m.Name) <= 2 {
-		return false
+// Archived reports whether the member has been deleted. Archived members are
+// kept until they are purged, and can be restored until then.
+func (m *Member) Archived() bool {
+	return m.DeletedAt != nil
+}
+
+// Tenure is the time a member has been with the club, in whole months.
+type Tenure struct {
+	Years  int
+	Months int
+}
+
+// Tenure returns the time between the member's join date and now.
+func (m *Member) Tenure(now time.Time) Tenure {
+	if now.Before(m.JoinDate) {
+		return Tenure{}
 	}
+	months := (now.Year()-m.JoinDate.Year())*12 + int(now.Month()-m.JoinDate.Month())
+	if now.Day() < m.JoinDate.Day() {
+		months--
+	}
+	return Tenure{Years: months / 12, Months: months % 12}
+}
+
+// MemberListOptions selects a page of members, optionally only those with Status,
+// the CustomFields values and every one of Tags, and matching Segment. Archived
+// selects the archived members instead of the current ones.
+type MemberListOptions struct {
+	ListOptions
+	Status       *MemberStatus
+	CustomFields map[string]any
+	Tags         []string
+	Segment      *SegmentFilter
+	Archived     bool
+}
 
-	if !regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`).MatchString(m.Email) {
-		return false
+var emailRegexp = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// Validate returns a *ValidationError listing the invalid fields of the member, or
+// nil. Custom field values are checked against the definitions in fields.
+func (m *Member) Validate(fields []*CustomField) error {
+	var verr ValidationError
+
+	switch {
+	case m.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case len(m.Name) <= 2:
+		verr.Add("name", CodeTooShort, "Name must be at least 3 characters")
+	}
+
+	switch {
+	case m.Email == "":
+		verr.Add("email", CodeRequired, "Email is required")
+	case !emailRegexp.MatchString(m.Email):
+		verr.Add("email", CodeInvalidFormat, "Email is not a valid email address")
 	}
 
-	if len(m.PhoneNumber) != 10 {
-		return false
+	switch {
+	case m.PhoneNumber == "":
+		verr.Add("phone_number", CodeRequired, "Phone number is required")
+	case !ValidPhone(m.PhoneNumber):
+		verr.Add("phone_number", CodeInvalidFormat, "Phone number is not a valid phone number")
 	}
 
-	if m.Status != MemberStatusInactive && m.Status != MemberStatusActive {
-		return false
+	switch {
+	case m.JoinDate.IsZero():
+		verr.Add("join_date", CodeRequired, "Join date is required")
+	case m.JoinDate.After(time.Now()):
+		verr.Add("join_date", CodeOutOfRange, "Join date must not be in the future")
 	}
 
-	return true
+	if _, ok := MemberStatusMap[m.Status]; !ok {
+		verr.Add("status", CodeInvalidValue, "Status is not a known member status")
+	}
+
+	validateCustomFields(&verr, m.CustomFields, fields)
+
+	return verr.Err()
 }
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberStatusReason is why a member's status was changed.
+type MemberStatusReason string
+
+var (
+	MemberStatusReasonMovedAway    MemberStatusReason = "moved_away"
+	MemberStatusReasonNonPayment   MemberStatusReason = "non_payment"
+	MemberStatusReasonMedical      MemberStatusReason = "medical"
+	MemberStatusReasonDisciplinary MemberStatusReason = "disciplinary"
+	MemberStatusReasonReinstated   MemberStatusReason = "reinstated"
+	MemberStatusReasonOther        MemberStatusReason = "other"
+)
+
+// MemberStatusChange records a change of a member's status, why and by whom.
+type MemberStatusChange struct {
+	ID        uuid.UUID          `db:"id"`
+	MemberID  uuid.UUID          `db:"member_id"`
+	From      MemberStatus       `db:"from_status"`
+	To        MemberStatus       `db:"to_status"`
+	Reason    MemberStatusReason `db:"reason"`
+	Note      string             `db:"note"`
+	Actor     string             `db:"actor"`
+	ChangedAt time.Time          `db:"changed_at"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the status
+// change, or nil. Fields are named as in the member update request. Reinstated
+// is only a reason for making a member active, and other needs a note.
+func (c *MemberStatusChange) Validate() error {
+	var verr ValidationError
+
+	switch c.Reason {
+	case MemberStatusReasonMovedAway, MemberStatusReasonNonPayment, MemberStatusReasonMedical, MemberStatusReasonDisciplinary:
+	case MemberStatusReasonReinstated:
+		if c.To != MemberStatusActive {
+			verr.Add("status_reason", CodeInvalidValue, "Reinstated is only a reason to make a member active")
+		}
+	case MemberStatusReasonOther:
+		if strings.TrimSpace(c.Note) == "" {
+			verr.Add("status_note", CodeRequired, "A note is required with reason other")
+		}
+	case "":
+		verr.Add("status_reason", CodeRequired, "A reason is required to change the status")
+	default:
+		verr.Add("status_reason", CodeInvalidValue, "Reason must be moved_away, non_payment, medical, disciplinary, reinstated or other")
+	}
+
+	return verr.Err()
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemberStatusChangeValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change MemberStatusChange
+		valid  bool
+	}{
+		{"reason", MemberStatusChange{From: MemberStatusActive, To: MemberStatusSuspended, Reason: MemberStatusReasonNonPayment}, true},
+		{"no reason", MemberStatusChange{From: MemberStatusActive, To: MemberStatusSuspended}, false},
+		{"unknown reason", MemberStatusChange{From: MemberStatusActive, To: MemberStatusSuspended, Reason: "bored"}, false},
+		{"reinstated", MemberStatusChange{From: MemberStatusSuspended, To: MemberStatusActive, Reason: MemberStatusReasonReinstated}, true},
+		{"reinstated to inactive", MemberStatusChange{From: MemberStatusBanned, To: MemberStatusInactive, Reason: MemberStatusReasonReinstated}, false},
+		{"other with note", MemberStatusChange{From: MemberStatusInactive, To: MemberStatusActive, Reason: MemberStatusReasonOther, Note: "Data entry error"}, true},
+		{"other without note", MemberStatusChange{From: MemberStatusInactive, To: MemberStatusActive, Reason: MemberStatusReasonOther, Note: " "}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+			var verr *ValidationError
+			if !tt.valid && !errors.As(err, &verr) {
+				t.Errorf("Validate() = %v, want *ValidationError", err)
+			}
+		})
+	}
+}
@@ -1,18 +1,68 @@
 package model
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrInvalidTransition = errors.New("invalid membership status transition")
+
 type MembershipStatus int
 
+// The values of MembershipExpired and MembershipActive are kept from the former
+// inactive and active statuses, so existing rows keep their meaning.
 var (
-	MembershipActive   MembershipStatus = 1
-	MembershipInactive MembershipStatus = 0
+	MembershipExpired        MembershipStatus = 0
+	MembershipActive         MembershipStatus = 1
+	MembershipFrozen         MembershipStatus = 2
+	MembershipPendingPayment MembershipStatus = 3
+	MembershipGrace          MembershipStatus = 4
+	MembershipCancelled      MembershipStatus = 5
 )
 
+var MembershipStatusMap = map[MembershipStatus]string{
+	MembershipExpired:        "expired",
+	MembershipActive:         "active",
+	MembershipFrozen:         "frozen",
+	MembershipPendingPayment: "pending_payment",
+	MembershipGrace:          "grace",
+	MembershipCancelled:      "cancelled",
+}
+
+// membershipTransitions lists the statuses a membership may move to from each
+// status. Expired and cancelled memberships are final.
+var membershipTransitions = map[MembershipStatus][]MembershipStatus{
+	MembershipPendingPayment: {MembershipActive, MembershipCancelled},
+	MembershipActive:         {MembershipGrace, MembershipExpired, MembershipFrozen, MembershipCancelled},
+	MembershipGrace:          {MembershipActive, MembershipExpired, MembershipCancelled},
+	MembershipFrozen:         {MembershipActive, MembershipCancelled},
+}
+
+// ParseMembershipStatus returns the status with the given name, e.g. "pending_payment".
+func ParseMembershipStatus(name string) (MembershipStatus, bool) {
+	for status, n := range MembershipStatusMap {
+		if n == name {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// MembershipTransition records a change of a membership's status. From is nil
+// for the transition that created the membership.
+type MembershipTransition struct {
+	ID           uuid.UUID         `db:"id"`
+	MembershipID uuid.UUID         `db:"membership_id"`
+	From         *MembershipStatus `db:"from_status"`
+	To           MembershipStatus  `db:"to_status"`
+	Actor        string            `db:"actor"`
+	Reason       string            `db:"reason"`
+	ChangedAt    time.Time         `db:"changed_at"`
+}
+
 type MembershipType string
 
 var (
@@ -24,29 +74,91 @@ type Membership struct {
 	ID        uuid.UUID        `db:"id"`
 	MemberID  uuid.UUID        `db:"member_id"`
 	SportID   uuid.UUID        `db:"sport_id"`
+	PlanID    *uuid.UUID       `db:"plan_id"`
 	Type      MembershipType   `db:"type"`
 	StartDate time.Time        `db:"start_date"`
 	DueDate   time.Time        `db:"due_date"`
 	Status    MembershipStatus `db:"status"`
-	Fee       float64          `db:"fee"`
+	Fee       Money            `db:"fees"`
+	AutoRenew bool             `db:"auto_renew"`
+	// BatchID is the training batch of the sport the membership is enrolled in, if any.
+	BatchID *uuid.UUID `db:"batch_id"`
 }
 
-func (m *Membership) Valid() bool {
+// Validate returns a *ValidationError listing the invalid fields of the membership, or nil.
+func (m *Membership) Validate() error {
+	var verr ValidationError
+
 	if m.Type != MembershipTypeMembership && m.Type != MembershipTypeTraining {
-		return false
+		verr.Add("type", CodeInvalidValue, "Type must be membership or training")
 	}
 
-	if m.Fee <= 0 {
-		return false
+	switch {
+	case !m.Fee.Valid():
+		verr.Add("fee", CodeInvalidFormat, "Fee must have a valid currency")
+	// A fee may be zero when a plan change is fully paid by the previous membership.
+	case m.Fee.IsNegative():
+		verr.Add("fee", CodeOutOfRange, "Fee must not be negative")
 	}
 
-	if m.Status != MembershipActive && m.Status != MembershipInactive {
-		return false
+	if _, ok := MembershipStatusMap[m.Status]; !ok {
+		verr.Add("status", CodeInvalidValue, "Status is not a known membership status")
 	}
 
 	if m.StartDate.After(m.DueDate) {
-		return false
+		verr.Add("due_date", CodeOutOfRange, "Due date must not be before the start date")
 	}
 
-	return true
+	return verr.Err()
+}
+
+// CanTransition reports whether the membership may move from its current status to status.
+func (m *Membership) CanTransition(status MembershipStatus) bool {
+	for _, next := range membershipTransitions[m.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition returns the record of moving the membership to status, or
+// ErrInvalidTransition if the move is not allowed. The membership is not changed.
+func (m *Membership) Transition(status MembershipStatus, actor, reason string) (*MembershipTransition, error) {
+	if !m.CanTransition(status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, MembershipStatusMap[m.Status], MembershipStatusMap[status])
+	}
+	from := m.Status
+	return &MembershipTransition{
+		MembershipID: m.ID,
+		From:         &from,
+		To:           status,
+		Actor:        actor,
+		Reason:       reason,
+	}, nil
+}
+
+// NextPeriod returns the membership for the period following m, on plan if m has
+// one. The new period starts on m's due date and awaits payment. On a plan it
+// runs for the plan's billing period at the plan's price, so neither a prorated
+// fee nor a freeze extension of m carries over; otherwise it has the same length
+// and fee as m.
+func (m *Membership) NextPeriod(plan *Plan) *Membership {
+	next := &Membership{
+		MemberID:  m.MemberID,
+		SportID:   m.SportID,
+		PlanID:    m.PlanID,
+		Type:      m.Type,
+		StartDate: m.DueDate,
+		DueDate:   m.DueDate.Add(m.DueDate.Sub(m.StartDate)),
+		Status:    MembershipPendingPayment,
+		Fee:       m.Fee,
+		AutoRenew: m.AutoRenew,
+		BatchID:   m.BatchID,
+	}
+	if plan != nil {
+		next.DueDate = plan.DueDate(next.StartDate)
+		next.Fee = plan.Price
+	}
+	return next
 }
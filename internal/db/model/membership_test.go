@@ -0,0 +1,119 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMembershipCanTransition(t *testing.T) {
+	allowed := map[MembershipStatus][]MembershipStatus{
+		MembershipPendingPayment: {MembershipActive, MembershipCancelled},
+		MembershipActive:         {MembershipGrace, MembershipExpired, MembershipFrozen, MembershipCancelled},
+		MembershipGrace:          {MembershipActive, MembershipExpired, MembershipCancelled},
+		MembershipFrozen:         {MembershipActive, MembershipCancelled},
+		MembershipExpired:        {},
+		MembershipCancelled:      {},
+	}
+	for from := range MembershipStatusMap {
+		for to := range MembershipStatusMap {
+			want := false
+			for _, s := range allowed[from] {
+				want = want || s == to
+			}
+			name := MembershipStatusMap[from] + " to " + MembershipStatusMap[to]
+			t.Run(name, func(t *testing.T) {
+				m := Membership{Status: from}
+				if got := m.CanTransition(to); got != want {
+					t.Errorf("CanTransition = %v, want %v", got, want)
+				}
+
+				transition, err := m.Transition(to, "staff", "reason")
+				switch {
+				case want && err != nil:
+					t.Errorf("Transition error = %v, want nil", err)
+				case want && (transition.From == nil || *transition.From != from || transition.To != to):
+					t.Errorf("Transition = %+v, want %s", transition, name)
+				case !want && !errors.Is(err, ErrInvalidTransition):
+					t.Errorf("Transition error = %v, want %v", err, ErrInvalidTransition)
+				}
+				if m.Status != from {
+					t.Errorf("Transition changed the status to %s", MembershipStatusMap[m.Status])
+				}
+			})
+		}
+	}
+}
+
+func TestMembershipNextPeriod(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	inr := func(amount int64) Money {
+		return Money{Amount: amount, Currency: "INR"}
+	}
+	planID := uuid.New()
+	plan := &Plan{ID: planID, BillingPeriod: BillingPeriodMonthly, Price: inr(300000)}
+
+	tests := []struct {
+		name       string
+		membership Membership
+		plan       *Plan
+		wantDue    time.Time
+		wantFee    Money
+	}{
+		{
+			name:       "on plan",
+			membership: Membership{PlanID: &planID, StartDate: date(time.January, 15), DueDate: date(time.February, 15), Fee: inr(300000)},
+			plan:       plan,
+			wantDue:    date(time.March, 15),
+			wantFee:    inr(300000),
+		},
+		{
+			name:       "prorated fee on plan",
+			membership: Membership{PlanID: &planID, StartDate: date(time.January, 20), DueDate: date(time.February, 20), Fee: inr(0)},
+			plan:       plan,
+			wantDue:    date(time.March, 20),
+			wantFee:    inr(300000),
+		},
+		{
+			name:       "freeze extension on plan",
+			membership: Membership{PlanID: &planID, StartDate: date(time.January, 1), DueDate: date(time.February, 11), Fee: inr(300000)},
+			plan:       plan,
+			wantDue:    date(time.March, 11),
+			wantFee:    inr(300000),
+		},
+		{
+			name:       "month end on plan",
+			membership: Membership{PlanID: &planID, StartDate: date(time.December, 31).AddDate(-1, 0, 0), DueDate: date(time.January, 31), Fee: inr(300000)},
+			plan:       plan,
+			wantDue:    date(time.February, 29),
+			wantFee:    inr(300000),
+		},
+		{
+			name:       "without plan",
+			membership: Membership{StartDate: date(time.January, 1), DueDate: date(time.January, 11), Fee: inr(50000)},
+			wantDue:    date(time.January, 21),
+			wantFee:    inr(50000),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.membership.AutoRenew = true
+			next := tt.membership.NextPeriod(tt.plan)
+			if !next.StartDate.Equal(tt.membership.DueDate) || !next.DueDate.Equal(tt.wantDue) {
+				t.Errorf("NextPeriod period = %s to %s, want %s to %s",
+					next.StartDate.Format(time.DateOnly), next.DueDate.Format(time.DateOnly),
+					tt.membership.DueDate.Format(time.DateOnly), tt.wantDue.Format(time.DateOnly))
+			}
+			if next.Fee != tt.wantFee {
+				t.Errorf("NextPeriod fee = %v, want %v", next.Fee, tt.wantFee)
+			}
+			if next.Status != MembershipPendingPayment || !next.AutoRenew {
+				t.Errorf("NextPeriod = %+v, want pending payment with auto renew", next)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("invalid currency")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+var currencyRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// currencyMinorDigits lists the ISO 4217 currencies that do not use two minor digits.
+var currencyMinorDigits = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// Money is an exact amount of a currency, stored in the currency's minor units
+// (e.g. cents for USD, paise for INR).
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney parses a decimal amount such as "1250.50" in the given ISO 4217 currency.
+// Amounts with more decimals than the currency has minor digits are rejected.
+func NewMoney(amount, currency string) (Money, error) {
+	if !currencyRegexp.MatchString(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	sign := int64(1)
+	if strings.HasPrefix(whole, "-") {
+		sign = -1
+		whole = whole[1:]
+	}
+
+	digits := minorDigits(currency)
+	if whole == "" || len(frac) > digits || strings.ContainsAny(whole+frac, "+-") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	units, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", digits-len(frac)), 10)
+	if !ok || !units.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	return Money{Amount: sign * units.Int64(), Currency: currency}, nil
+}
+
+func minorDigits(currency string) int {
+	if digits, ok := currencyMinorDigits[currency]; ok {
+		return digits
+	}
+	return 2
+}
+
+func (m Money) Valid() bool {
+	return currencyRegexp.MatchString(m.Currency)
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns m + o. It panics if the currencies differ.
+func (m Money) Add(o Money) Money {
+	m.mustMatch(o)
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}
+}
+
+// Sub returns m - o. It panics if the currencies differ.
+func (m Money) Sub(o Money) Money {
+	m.mustMatch(o)
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}
+}
+
+// Cmp compares m and o and returns -1, 0 or +1. It panics if the currencies differ.
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SameCurrency reports whether m and o are in the same currency.
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency == o.Currency
+}
+
+func (m Money) mustMatch(o Money) {
+	if !m.SameCurrency(o) {
+		panic(fmt.Sprintf("%v: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency))
+	}
+}
+
+// Decimal returns the amount as a decimal string with the currency's minor digits, e.g. "1250.50".
+func (m Money) Decimal() string {
+	digits := minorDigits(m.Currency)
+	units := m.Amount
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	s := fmt.Sprintf("%0*d", digits+1, units)
+	if digits == 0 {
+		return sign + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes m as {"amount": "1250.50", "currency": "INR"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes {"amount": "1250.50", "currency": "INR"}. The amount
+// must be a string so that it is never rounded through a float.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+	money, err := NewMoney(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+// NumericValue encodes the amount as a PostgreSQL NUMERIC.
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{
+		Int:   big.NewInt(m.Amount),
+		Exp:   int32(-minorDigits(m.Currency)),
+		Valid: true,
+	}, nil
+}
+
+// ScanNumeric decodes a PostgreSQL NUMERIC into the amount. The currency must
+// be scanned before the amount so that the minor digits are known.
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid || v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("%w: cannot scan %v into money", ErrInvalidAmount, v)
+	}
+
+	units := new(big.Int).Set(v.Int)
+	exp := int64(v.Exp) + int64(minorDigits(m.Currency))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(exp)), nil)
+	if exp >= 0 {
+		units.Mul(units, scale)
+	} else {
+		var rem big.Int
+		units.QuoRem(units, scale, &rem)
+		if rem.Sign() != 0 {
+			return fmt.Errorf("%w: %v has more than %d minor digits", ErrInvalidAmount, v, minorDigits(m.Currency))
+		}
+	}
+
+	if !units.IsInt64() {
+		return fmt.Errorf("%w: %v is out of range", ErrInvalidAmount, v)
+	}
+	m.Amount = units.Int64()
+	return nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
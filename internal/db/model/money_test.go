@@ -0,0 +1,194 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{"1250.50", "INR", 125050, nil},
+		{"1250.5", "INR", 125050, nil},
+		{"1250", "INR", 125000, nil},
+		{" 0.01 ", "USD", 1, nil},
+		{"-3.25", "USD", -325, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"1.005", "INR", 0, ErrInvalidAmount},
+		{"1.5", "JPY", 0, ErrInvalidAmount},
+		{"", "INR", 0, ErrInvalidAmount},
+		{".50", "INR", 0, ErrInvalidAmount},
+		{"+1", "INR", 0, ErrInvalidAmount},
+		{"--1", "INR", 0, ErrInvalidAmount},
+		{"1e3", "INR", 0, ErrInvalidAmount},
+		{"1,000", "INR", 0, ErrInvalidAmount},
+		{"99999999999999999999", "INR", 0, ErrInvalidAmount},
+		{"10", "inr", 0, ErrInvalidCurrency},
+		{"10", "RUPEE", 0, ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			got, err := NewMoney(tt.amount, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewMoney(%q, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.wantErr)
+			}
+			if err == nil && (got.Amount != tt.want || got.Currency != tt.currency) {
+				t.Errorf("NewMoney(%q, %q) = %+v, want %d %s", tt.amount, tt.currency, got, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{125050, "INR"}, "1250.50"},
+		{Money{5, "INR"}, "0.05"},
+		{Money{0, "INR"}, "0.00"},
+		{Money{-325, "USD"}, "-3.25"},
+		{Money{-5, "USD"}, "-0.05"},
+		{Money{1500, "JPY"}, "1500"},
+		{Money{-7, "JPY"}, "-7"},
+		{Money{1234, "KWD"}, "1.234"},
+		{Money{1, "KWD"}, "0.001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want+" "+tt.money.Currency, func(t *testing.T) {
+			if got := tt.money.Decimal(); got != tt.want {
+				t.Errorf("%+v.Decimal() = %q, want %q", tt.money, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		money Money
+		json  string
+	}{
+		{Money{125050, "INR"}, `{"amount":"1250.50","currency":"INR"}`},
+		{Money{-325, "USD"}, `{"amount":"-3.25","currency":"USD"}`},
+		{Money{1500, "JPY"}, `{"amount":"1500","currency":"JPY"}`},
+		{Money{1234, "KWD"}, `{"amount":"1.234","currency":"KWD"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			data, err := json.Marshal(tt.money)
+			if err != nil {
+				t.Fatalf("Marshal(%+v) error = %v", tt.money, err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("Marshal(%+v) = %s, want %s", tt.money, data, tt.json)
+			}
+
+			var got Money
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", data, err)
+			}
+			if got != tt.money {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", data, got, tt.money)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSONInvalid(t *testing.T) {
+	tests := []struct {
+		json    string
+		wantErr error
+	}{
+		{`{"amount":1250.5,"currency":"INR"}`, ErrInvalidAmount},
+		{`{"amount":"12.345","currency":"INR"}`, ErrInvalidAmount},
+		{`{"amount":"10","currency":""}`, ErrInvalidCurrency},
+		{`"1250.50 INR"`, ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var m Money
+			if err := json.Unmarshal([]byte(tt.json), &m); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Unmarshal(%s) error = %v, want %v", tt.json, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMoneyNumeric(t *testing.T) {
+	tests := []struct {
+		money Money
+	}{
+		{Money{125050, "INR"}},
+		{Money{-325, "USD"}},
+		{Money{1500, "JPY"}},
+		{Money{1234, "KWD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.money.String(), func(t *testing.T) {
+			v, err := tt.money.NumericValue()
+			if err != nil {
+				t.Fatalf("NumericValue() error = %v", err)
+			}
+			got := Money{Currency: tt.money.Currency}
+			if err := got.ScanNumeric(v); err != nil {
+				t.Fatalf("ScanNumeric(%v) error = %v", v, err)
+			}
+			if got != tt.money {
+				t.Errorf("ScanNumeric(NumericValue()) = %+v, want %+v", got, tt.money)
+			}
+		})
+	}
+
+	// NUMERIC(12,3) columns hold INR amounts with a third, zero, decimal.
+	m := Money{Currency: "INR"}
+	if err := m.ScanNumeric(pgtype.Numeric{Int: big.NewInt(1250500), Exp: -3, Valid: true}); err != nil || m.Amount != 125050 {
+		t.Errorf("ScanNumeric(1250.500) = %+v, %v, want 125050", m, err)
+	}
+	if err := m.ScanNumeric(pgtype.Numeric{Int: big.NewInt(1250505), Exp: -3, Valid: true}); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("ScanNumeric(1250.505) error = %v, want %v", err, ErrInvalidAmount)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := Money{1000, "INR"}, Money{250, "INR"}
+	if got := a.Add(b); got != (Money{1250, "INR"}) {
+		t.Errorf("Add = %+v, want 1250 INR", got)
+	}
+	if got := b.Sub(a); got != (Money{-750, "INR"}) {
+		t.Errorf("Sub = %+v, want -750 INR", got)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(a) != 0 {
+		t.Errorf("Cmp(%v, %v) = %d, %d, %d, want 1, -1, 0", a, b, a.Cmp(b), b.Cmp(a), a.Cmp(a))
+	}
+}
+
+func TestMoneyCurrencyMismatchPanics(t *testing.T) {
+	inr, usd := Money{1000, "INR"}, Money{1000, "USD"}
+	tests := []struct {
+		name string
+		op   func()
+	}{
+		{"Add", func() { inr.Add(usd) }},
+		{"Sub", func() { inr.Sub(usd) }},
+		{"Cmp", func() { inr.Cmp(usd) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s of INR and USD did not panic", tt.name)
+				}
+			}()
+			tt.op()
+		})
+	}
+}
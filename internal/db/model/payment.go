@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentStatus int
+
+const (
+	PaymentStatusPending PaymentStatus = iota
+	PaymentStatusCompleted
+	PaymentStatusFailed
+)
+
+var PaymentStatusMap = map[PaymentStatus]string{
+	PaymentStatusPending:   "Pending",
+	PaymentStatusCompleted: "Completed",
+	PaymentStatusFailed:    "Failed",
+}
+
+type Payment struct {
+	ID           uuid.UUID     `db:"id"`
+	MembershipID uuid.UUID     `db:"membership_id"`
+	Amount       Money         `db:"amount"`
+	PaymentDate  time.Time     `db:"payment_date"`
+	Status       PaymentStatus `db:"status"`
+	PaymentLink  string        `db:"payment_link"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the payment, or nil.
+func (p *Payment) Validate() error {
+	var verr ValidationError
+
+	if p.MembershipID == uuid.Nil {
+		verr.Add("membership_id", CodeRequired, "Membership ID is required")
+	}
+
+	switch {
+	case p.Amount.Currency == "":
+		verr.Add("amount", CodeRequired, "Amount is required")
+	case !p.Amount.Valid():
+		verr.Add("amount", CodeInvalidFormat, "Amount must have a valid currency")
+	case !p.Amount.IsPositive():
+		verr.Add("amount", CodeOutOfRange, "Amount must be positive")
+	}
+
+	if _, ok := PaymentStatusMap[p.Status]; !ok {
+		verr.Add("status", CodeInvalidValue, "Status is not a known payment status")
+	}
+
+	return verr.Err()
+}
+
+// Balance returns the amount still owed on the membership after deducting the
+// completed payments. Payments in another currency than the fee are ignored.
+func Balance(membership *Membership, payments []*Payment) Money {
+	balance := membership.Fee
+	for _, payment := range payments {
+		if payment.Status == PaymentStatusCompleted && payment.Amount.SameCurrency(balance) {
+			balance = balance.Sub(payment.Amount)
+		}
+	}
+	return balance
+}
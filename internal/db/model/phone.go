@@ -0,0 +1,105 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// DefaultPhoneRegion is the region numbers without a country code are read as,
+// unless another is configured.
+const DefaultPhoneRegion = "IN"
+
+// ValidPhoneRegion reports whether region is a CLDR region code with a numbering plan, e.g. "IN" or "GB".
+func ValidPhoneRegion(region string) bool {
+	return phonenumbers.GetCountryCodeForRegion(region) != 0
+}
+
+// NormalizePhone returns the phone number in E.164 form, e.g. +919876543210,
+// however it was typed. Numbers without a country code are read as numbers of
+// region. The number is returned unchanged if it is not a valid number.
+func NormalizePhone(number, region string) string {
+	parsed, err := phonenumbers.Parse(number, region)
+	if err != nil || !phonenumbers.IsValidNumber(parsed) {
+		return number
+	}
+	return phonenumbers.Format(parsed, phonenumbers.E164)
+}
+
+// ValidPhone reports whether number is in E.164 form and valid under the
+// numbering plan of its country.
+func ValidPhone(number string) bool {
+	parsed, err := phonenumbers.Parse(number, "")
+	if err != nil {
+		return false
+	}
+	return phonenumbers.IsValidNumber(parsed) && phonenumbers.Format(parsed, phonenumbers.E164) == number
+}
+
+// PhoneIssue is a stored phone number that cannot be normalized, either because
+// it is not a valid number or because its normalized form is another member's.
+type PhoneIssue struct {
+	MemberID      uuid.UUID
+	Phone         string
+	Normalized    string
+	ConflictsWith *uuid.UUID
+}
+
+// PhoneNormalization is the plan for rewriting stored phone numbers in E.164 form.
+type PhoneNormalization struct {
+	Changes   map[uuid.UUID]string // The normalized numbers, by member ID.
+	Unchanged int
+	Invalid   []PhoneIssue
+	Conflicts []PhoneIssue
+}
+
+// NormalizePhones plans the normalization of the phone numbers, by member ID,
+// reading numbers without a country code as numbers of region. Numbers already
+// in E.164 form keep their owner; of the others normalizing to the same number,
+// the member with the lowest ID gets it.
+func NormalizePhones(phones map[uuid.UUID]string, region string) *PhoneNormalization {
+	ids := slices.SortedFunc(maps.Keys(phones), func(a, b uuid.UUID) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	n := &PhoneNormalization{
+		Changes:   make(map[uuid.UUID]string),
+		Invalid:   []PhoneIssue{},
+		Conflicts: []PhoneIssue{},
+	}
+
+	owners := make(map[string]uuid.UUID)
+	for _, id := range ids {
+		if ValidPhone(phones[id]) {
+			owners[phones[id]] = id
+			n.Unchanged++
+		}
+	}
+
+	for _, id := range ids {
+		phone := phones[id]
+		if ValidPhone(phone) {
+			continue
+		}
+		normalized := NormalizePhone(phone, region)
+		if !ValidPhone(normalized) {
+			n.Invalid = append(n.Invalid, PhoneIssue{MemberID: id, Phone: phone})
+			continue
+		}
+		if owner, ok := owners[normalized]; ok {
+			n.Conflicts = append(n.Conflicts, PhoneIssue{
+				MemberID:      id,
+				Phone:         phone,
+				Normalized:    normalized,
+				ConflictsWith: &owner,
+			})
+			continue
+		}
+		owners[normalized] = id
+		n.Changes[id] = normalized
+	}
+	return n
+}
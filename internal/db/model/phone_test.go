@@ -0,0 +1,122 @@
+package model
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		region string
+		want   string
+	}{
+		{"national number", "98765 43210", "IN", "+919876543210"},
+		{"trunk prefix", "098765-43210", "IN", "+919876543210"},
+		{"international number", "+91 98765 43210", "GB", "+919876543210"},
+		{"international prefix", "0044 20 7946 0958", "IN", "+442079460958"},
+		{"other region", "020 7946 0958", "GB", "+442079460958"},
+		{"already normalized", "+919876543210", "IN", "+919876543210"},
+		{"invalid number", "12345", "IN", "12345"},
+		{"not a number", "n/a", "IN", "n/a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePhone(tt.number, tt.region); got != tt.want {
+				t.Errorf("NormalizePhone(%q, %q) = %q, want %q", tt.number, tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidPhone(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{"+919876543210", true},
+		{"+442079460958", true},
+		{"+91 98765 43210", false},
+		{"9876543210", false},
+		{"+9112345", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := ValidPhone(tt.number); got != tt.want {
+				t.Errorf("ValidPhone(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePhones(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	b := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	c := uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+
+	tests := []struct {
+		name          string
+		phones        map[uuid.UUID]string
+		wantChanges   map[uuid.UUID]string
+		wantUnchanged int
+		wantInvalid   []uuid.UUID
+		wantConflicts map[uuid.UUID]uuid.UUID
+	}{
+		{
+			name:          "normalizes",
+			phones:        map[uuid.UUID]string{a: "98765 43210", b: "+919876543211"},
+			wantChanges:   map[uuid.UUID]string{a: "+919876543210"},
+			wantUnchanged: 1,
+		},
+		{
+			name:        "reports invalid",
+			phones:      map[uuid.UUID]string{a: "12345"},
+			wantChanges: map[uuid.UUID]string{},
+			wantInvalid: []uuid.UUID{a},
+		},
+		{
+			name:          "normalized number keeps its owner",
+			phones:        map[uuid.UUID]string{a: "98765 43210", b: "+919876543210"},
+			wantChanges:   map[uuid.UUID]string{},
+			wantUnchanged: 1,
+			wantConflicts: map[uuid.UUID]uuid.UUID{a: b},
+		},
+		{
+			name:          "lowest ID gets the number",
+			phones:        map[uuid.UUID]string{c: "09876543210", a: "98765 43210", b: "+91 98765-43210"},
+			wantChanges:   map[uuid.UUID]string{a: "+919876543210"},
+			wantConflicts: map[uuid.UUID]uuid.UUID{b: a, c: a},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizePhones(tt.phones, "IN")
+			if !maps.Equal(got.Changes, tt.wantChanges) {
+				t.Errorf("Changes = %v, want %v", got.Changes, tt.wantChanges)
+			}
+			if got.Unchanged != tt.wantUnchanged {
+				t.Errorf("Unchanged = %d, want %d", got.Unchanged, tt.wantUnchanged)
+			}
+			if len(got.Invalid) != len(tt.wantInvalid) {
+				t.Fatalf("Invalid = %v, want members %v", got.Invalid, tt.wantInvalid)
+			}
+			for i, issue := range got.Invalid {
+				if issue.MemberID != tt.wantInvalid[i] || issue.Phone != tt.phones[issue.MemberID] {
+					t.Errorf("Invalid[%d] = %+v, want member %s", i, issue, tt.wantInvalid[i])
+				}
+			}
+			if len(got.Conflicts) != len(tt.wantConflicts) {
+				t.Fatalf("Conflicts = %v, want %v", got.Conflicts, tt.wantConflicts)
+			}
+			for _, issue := range got.Conflicts {
+				if owner, ok := tt.wantConflicts[issue.MemberID]; !ok || issue.ConflictsWith == nil || *issue.ConflictsWith != owner {
+					t.Errorf("conflict %+v, want owner %s", issue, owner)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BillingPeriod string
+
+var (
+	BillingPeriodMonthly   BillingPeriod = "monthly"
+	BillingPeriodQuarterly BillingPeriod = "quarterly"
+	BillingPeriodAnnual    BillingPeriod = "annual"
+)
+
+var billingPeriodMonths = map[BillingPeriod]int{
+	BillingPeriodMonthly:   1,
+	BillingPeriodQuarterly: 3,
+	BillingPeriodAnnual:    12,
+}
+
+type Plan struct {
+	ID            uuid.UUID      `db:"id"`
+	SportID       uuid.UUID      `db:"sport_id"`
+	Name          string         `db:"name"`
+	BillingPeriod BillingPeriod  `db:"billing_period"`
+	Price         Money          `db:"price"`
+	Type          MembershipType `db:"type"`
+	MaxFreezeDays int            `db:"max_freeze_days"`
+	MinFreezeDays int            `db:"min_freeze_days"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the plan, or nil.
+func (p *Plan) Validate() error {
+	var verr ValidationError
+
+	switch {
+	case p.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case len(p.Name) <= 2:
+		verr.Add("name", CodeTooShort, "Name must be at least 3 characters")
+	}
+
+	switch _, ok := billingPeriodMonths[p.BillingPeriod]; {
+	case p.BillingPeriod == "":
+		verr.Add("billing_period", CodeRequired, "Billing period is required")
+	case !ok:
+		verr.Add("billing_period", CodeInvalidValue, "Billing period must be monthly, quarterly or annual")
+	}
+
+	switch {
+	case p.Price.Currency == "":
+		verr.Add("price", CodeRequired, "Price is required")
+	case !p.Price.Valid():
+		verr.Add("price", CodeInvalidFormat, "Price must have a valid currency")
+	case !p.Price.IsPositive():
+		verr.Add("price", CodeOutOfRange, "Price must be positive")
+	}
+
+	switch {
+	case p.Type == "":
+		verr.Add("type", CodeRequired, "Type is required")
+	case p.Type != MembershipTypeMembership && p.Type != MembershipTypeTraining:
+		verr.Add("type", CodeInvalidValue, "Type must be membership or training")
+	}
+
+	if p.MinFreezeDays < 0 {
+		verr.Add("min_freeze_days", CodeOutOfRange, "Minimum freeze days must not be negative")
+	}
+	switch {
+	case p.MaxFreezeDays < 0:
+		verr.Add("max_freeze_days", CodeOutOfRange, "Maximum freeze days must not be negative")
+	case p.MinFreezeDays > p.MaxFreezeDays:
+		verr.Add("max_freeze_days", CodeOutOfRange, "Maximum freeze days must not be less than the minimum")
+	}
+
+	return verr.Err()
+}
+
+// DueDate returns the end of a billing period that starts on start. A period
+// starting on a day the end month does not have ends on the last day of that
+// month, e.g. a monthly period starting on 31 January ends on 28 February.
+func (p *Plan) DueDate(start time.Time) time.Time {
+	return addMonths(start, billingPeriodMonths[p.BillingPeriod])
+}
+
+// addMonths adds months to t, clamping the day to the end of the month rather
+// than overflowing into the next as time.AddDate does.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	// Day 0 of the month after the target month is the target month's last day.
+	last := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > last {
+		day = last
+	}
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month+time.Month(months), day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+// NewMembership returns a membership awaiting payment of the plan for the member, starting on start.
+func (p *Plan) NewMembership(memberID uuid.UUID, start time.Time) *Membership {
+	planID := p.ID
+	return &Membership{
+		MemberID:  memberID,
+		SportID:   p.SportID,
+		PlanID:    &planID,
+		Type:      p.Type,
+		StartDate: start,
+		DueDate:   p.DueDate(start),
+		Status:    MembershipPendingPayment,
+		Fee:       p.Price,
+	}
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanDueDate(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		period BillingPeriod
+		start  time.Time
+		want   time.Time
+	}{
+		{"monthly", BillingPeriodMonthly, date(2024, time.March, 15), date(2024, time.April, 15)},
+		{"monthly from end of January", BillingPeriodMonthly, date(2023, time.January, 31), date(2023, time.February, 28)},
+		{"monthly from end of January in leap year", BillingPeriodMonthly, date(2024, time.January, 31), date(2024, time.February, 29)},
+		{"monthly from 30 January", BillingPeriodMonthly, date(2023, time.January, 30), date(2023, time.February, 28)},
+		{"monthly from 31 March", BillingPeriodMonthly, date(2024, time.March, 31), date(2024, time.April, 30)},
+		{"monthly over year end", BillingPeriodMonthly, date(2024, time.December, 31), date(2025, time.January, 31)},
+		{"quarterly", BillingPeriodQuarterly, date(2024, time.January, 10), date(2024, time.April, 10)},
+		{"quarterly from end of November", BillingPeriodQuarterly, date(2024, time.November, 30), date(2025, time.February, 28)},
+		{"annual", BillingPeriodAnnual, date(2024, time.June, 1), date(2025, time.June, 1)},
+		{"annual from leap day", BillingPeriodAnnual, date(2024, time.February, 29), date(2025, time.February, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Plan{BillingPeriod: tt.period}
+			if got := p.DueDate(tt.start); !got.Equal(tt.want) {
+				t.Errorf("DueDate(%s) = %s, want %s", tt.start.Format(time.DateOnly), got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestPlanDueDateKeepsTime(t *testing.T) {
+	loc := time.FixedZone("IST", 5*60*60+30*60)
+	start := time.Date(2024, time.January, 31, 18, 45, 30, 0, loc)
+	want := time.Date(2024, time.February, 29, 18, 45, 30, 0, loc)
+
+	p := Plan{BillingPeriod: BillingPeriodMonthly}
+	if got := p.DueDate(start); !got.Equal(want) || got.Location() != loc {
+		t.Errorf("DueDate(%s) = %s, want %s", start, got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrMembershipPastDue = errors.New("membership is past its due date")
+
+// Proration is the price breakdown of moving a membership to another plan part
+// way through its period. The value of the days used so far is charged pro rata
+// and whatever was paid beyond it is credited against the price of the new plan.
+type Proration struct {
+	Date          time.Time
+	TotalDays     int
+	UsedDays      int
+	RemainingDays int
+	Fee           Money
+	Paid          Money
+	Used          Money
+	Credit        Money // Paid - Used; negative when the used days are not paid for yet.
+	Price         Money
+	AmountDue     Money // The fee of the new membership, never negative.
+	UnusedCredit  Money // Credit left over when it exceeds the price of the new plan, kept as member credit.
+}
+
+// Prorate computes the breakdown of closing the membership on date and moving to
+// the plan. The plan must be priced in the currency of the membership's fee, and
+// date must be before the membership's due date.
+func Prorate(membership *Membership, payments []*Payment, plan *Plan, date time.Time) (*Proration, error) {
+	if !date.Before(membership.DueDate) {
+		return nil, ErrMembershipPastDue
+	}
+	if !plan.Price.SameCurrency(membership.Fee) {
+		return nil, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, membership.Fee.Currency, plan.Price.Currency)
+	}
+
+	p := &Proration{
+		Date:      date,
+		TotalDays: wholeDays(membership.StartDate, membership.DueDate),
+		UsedDays:  wholeDays(membership.StartDate, date),
+		Fee:       membership.Fee,
+		Paid:      membership.Fee.Sub(Balance(membership, payments)),
+		Price:     plan.Price,
+	}
+	p.UsedDays = min(p.UsedDays, p.TotalDays)
+	p.RemainingDays = p.TotalDays - p.UsedDays
+
+	p.Used = membership.Fee
+	if p.TotalDays > 0 {
+		// Round half up to the nearest minor unit.
+		p.Used.Amount = (2*membership.Fee.Amount*int64(p.UsedDays) + int64(p.TotalDays)) / (2 * int64(p.TotalDays))
+	}
+	p.Credit = p.Paid.Sub(p.Used)
+
+	p.AmountDue = p.Price.Sub(p.Credit)
+	p.UnusedCredit = Money{Currency: p.Price.Currency}
+	if p.AmountDue.IsNegative() {
+		p.UnusedCredit.Amount = -p.AmountDue.Amount
+		p.AmountDue.Amount = 0
+	}
+	return p, nil
+}
+
+// NewMembership returns the membership on the new plan for the member, starting
+// on the date of the change and charging the amount due. It stays in the batch of
+// the membership if the plan is of the same sport.
+func (p *Proration) NewMembership(membership *Membership, plan *Plan) *Membership {
+	next := plan.NewMembership(membership.MemberID, p.Date)
+	next.Fee = p.AmountDue
+	next.AutoRenew = membership.AutoRenew
+	if plan.SportID == membership.SportID {
+		next.BatchID = membership.BatchID
+	}
+	if next.Fee.IsZero() {
+		next.Status = MembershipActive
+	}
+	return next
+}
+
+// MemberCredit returns the credit granted to the member for the unused credit
+// of the membership, or nil if there is none.
+func (p *Proration) MemberCredit(membership *Membership, reason string) *MemberCredit {
+	if !p.UnusedCredit.IsPositive() {
+		return nil
+	}
+	membershipID := membership.ID
+	return &MemberCredit{
+		MemberID:     membership.MemberID,
+		Amount:       p.UnusedCredit,
+		MembershipID: &membershipID,
+		Reason:       reason,
+	}
+}
+
+func wholeDays(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours() / 24)
+}
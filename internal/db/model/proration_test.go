@@ -0,0 +1,200 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestProrate(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	inr := func(amount int64) Money {
+		return Money{Amount: amount, Currency: "INR"}
+	}
+	paid := func(amounts ...int64) []*Payment {
+		payments := make([]*Payment, len(amounts))
+		for i, amount := range amounts {
+			payments[i] = &Payment{Amount: inr(amount), Status: PaymentStatusCompleted}
+		}
+		return payments
+	}
+
+	tests := []struct {
+		name         string
+		fee          Money
+		due          time.Time
+		payments     []*Payment
+		price        Money
+		date         time.Time
+		wantUsed     Money
+		wantCredit   Money
+		wantDue      Money
+		wantUnused   Money
+		wantUsedDays int
+		wantErr      error
+	}{
+		{
+			name: "paid in full", fee: inr(300000), due: day(31), payments: paid(300000),
+			price: inr(500000), date: day(11),
+			wantUsedDays: 10, wantUsed: inr(100000), wantCredit: inr(200000), wantDue: inr(300000), wantUnused: inr(0),
+		},
+		{
+			name: "rounds used value to nearest", fee: inr(100000), due: day(4), payments: paid(100000),
+			price: inr(100000), date: day(3),
+			wantUsedDays: 2, wantUsed: inr(66667), wantCredit: inr(33333), wantDue: inr(66667), wantUnused: inr(0),
+		},
+		{
+			name: "rounds half up", fee: inr(5), due: day(3), payments: paid(5),
+			price: inr(10), date: day(2),
+			wantUsedDays: 1, wantUsed: inr(3), wantCredit: inr(2), wantDue: inr(8), wantUnused: inr(0),
+		},
+		{
+			name: "unpaid", fee: inr(300000), due: day(31),
+			price: inr(500000), date: day(11),
+			wantUsedDays: 10, wantUsed: inr(100000), wantCredit: inr(-100000), wantDue: inr(600000), wantUnused: inr(0),
+		},
+		{
+			name: "partly paid", fee: inr(300000), due: day(31),
+			payments: append(paid(100000), &Payment{Amount: inr(200000), Status: PaymentStatusPending}),
+			price:    inr(500000), date: day(11),
+			wantUsedDays: 10, wantUsed: inr(100000), wantCredit: inr(0), wantDue: inr(500000), wantUnused: inr(0),
+		},
+		{
+			name: "credit beyond price", fee: inr(300000), due: day(31), payments: paid(300000),
+			price: inr(50000), date: day(11),
+			wantUsedDays: 10, wantUsed: inr(100000), wantCredit: inr(200000), wantDue: inr(0), wantUnused: inr(150000),
+		},
+		{
+			name: "on start date", fee: inr(300000), due: day(31), payments: paid(300000),
+			price: inr(300000), date: day(1),
+			wantUsedDays: 0, wantUsed: inr(0), wantCredit: inr(300000), wantDue: inr(0), wantUnused: inr(0),
+		},
+		{
+			name: "on due date", fee: inr(300000), due: day(31), payments: paid(300000),
+			price: inr(300000), date: day(31),
+			wantErr: ErrMembershipPastDue,
+		},
+		{
+			name: "other currency", fee: inr(300000), due: day(31), payments: paid(300000),
+			price: Money{Amount: 5000, Currency: "USD"}, date: day(11),
+			wantErr: ErrCurrencyMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			membership := &Membership{StartDate: day(1), DueDate: tt.due, Fee: tt.fee}
+			plan := &Plan{Price: tt.price}
+
+			p, err := Prorate(membership, tt.payments, plan, tt.date)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Prorate error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if p.UsedDays != tt.wantUsedDays || p.RemainingDays != p.TotalDays-tt.wantUsedDays {
+				t.Errorf("days used = %d, remaining = %d of %d, want %d used", p.UsedDays, p.RemainingDays, p.TotalDays, tt.wantUsedDays)
+			}
+			if p.Used != tt.wantUsed {
+				t.Errorf("Used = %v, want %v", p.Used, tt.wantUsed)
+			}
+			if p.Credit != tt.wantCredit {
+				t.Errorf("Credit = %v, want %v", p.Credit, tt.wantCredit)
+			}
+			if p.AmountDue != tt.wantDue {
+				t.Errorf("AmountDue = %v, want %v", p.AmountDue, tt.wantDue)
+			}
+			if p.UnusedCredit != tt.wantUnused {
+				t.Errorf("UnusedCredit = %v, want %v", p.UnusedCredit, tt.wantUnused)
+			}
+		})
+	}
+}
+
+func TestProrationCredit(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	batchID := uuid.New()
+	membership := &Membership{
+		ID:        uuid.New(),
+		MemberID:  uuid.New(),
+		SportID:   uuid.New(),
+		StartDate: start,
+		DueDate:   start.AddDate(0, 0, 30),
+		Fee:       Money{Amount: 300000, Currency: "INR"},
+		Status:    MembershipActive,
+		AutoRenew: true,
+		BatchID:   &batchID,
+	}
+	payments := []*Payment{{Amount: membership.Fee, Status: PaymentStatusCompleted}}
+	date := start.AddDate(0, 0, 10)
+
+	tests := []struct {
+		name       string
+		plan       *Plan
+		wantFee    Money
+		wantStatus MembershipStatus
+		wantCredit *Money
+		wantBatch  bool
+	}{
+		{
+			name:       "credit covers the plan",
+			plan:       &Plan{SportID: membership.SportID, BillingPeriod: BillingPeriodMonthly, Price: Money{Amount: 50000, Currency: "INR"}, Type: MembershipTypeTraining},
+			wantFee:    Money{Amount: 0, Currency: "INR"},
+			wantStatus: MembershipActive,
+			wantCredit: &Money{Amount: 150000, Currency: "INR"},
+			wantBatch:  true,
+		},
+		{
+			name:       "credit covers the plan exactly",
+			plan:       &Plan{SportID: uuid.New(), BillingPeriod: BillingPeriodMonthly, Price: Money{Amount: 200000, Currency: "INR"}, Type: MembershipTypeMembership},
+			wantFee:    Money{Amount: 0, Currency: "INR"},
+			wantStatus: MembershipActive,
+		},
+		{
+			name:       "amount due",
+			plan:       &Plan{SportID: uuid.New(), BillingPeriod: BillingPeriodMonthly, Price: Money{Amount: 500000, Currency: "INR"}, Type: MembershipTypeMembership},
+			wantFee:    Money{Amount: 300000, Currency: "INR"},
+			wantStatus: MembershipPendingPayment,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Prorate(membership, payments, tt.plan, date)
+			if err != nil {
+				t.Fatalf("Prorate error = %v", err)
+			}
+
+			next := p.NewMembership(membership, tt.plan)
+			if next.Fee != tt.wantFee || next.Status != tt.wantStatus {
+				t.Errorf("NewMembership fee = %v, status = %s, want %v, %s",
+					next.Fee, MembershipStatusMap[next.Status], tt.wantFee, MembershipStatusMap[tt.wantStatus])
+			}
+			if err := next.Validate(); err != nil {
+				t.Errorf("NewMembership Validate() = %v", err)
+			}
+			if !next.StartDate.Equal(date) || !next.AutoRenew {
+				t.Errorf("NewMembership = %+v, want start %s with auto renew", next, date)
+			}
+			if got := next.BatchID != nil && *next.BatchID == batchID; got != tt.wantBatch {
+				t.Errorf("NewMembership batch = %v, want kept %v", next.BatchID, tt.wantBatch)
+			}
+
+			credit := p.MemberCredit(membership, "Plan change")
+			switch {
+			case tt.wantCredit == nil && credit != nil:
+				t.Errorf("MemberCredit = %+v, want nil", credit)
+			case tt.wantCredit != nil && credit == nil:
+				t.Errorf("MemberCredit = nil, want %v", *tt.wantCredit)
+			case credit != nil:
+				if credit.Amount != *tt.wantCredit || credit.MemberID != membership.MemberID ||
+					credit.MembershipID == nil || *credit.MembershipID != membership.ID || credit.Reason != "Plan change" {
+					t.Errorf("MemberCredit = %+v, want %v for membership %s", credit, *tt.wantCredit, membership.ID)
+				}
+			}
+		})
+	}
+}
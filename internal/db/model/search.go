@@ -0,0 +1,132 @@
+package model
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+var (
+	MemberFieldName        = "name"
+	MemberFieldEmail       = "email"
+	MemberFieldPhoneNumber = "phone_number"
+	MemberFieldAddress     = "address"
+)
+
+// MemberMatch is a member found by a search, with the field that matched the
+// query best and its similarity score between 0 and 1.
+type MemberMatch struct {
+	Member *Member
+	Field  string
+	Score  float64
+}
+
+// Value returns the value of the matched field.
+func (m *MemberMatch) Value() string {
+	switch m.Field {
+	case MemberFieldEmail:
+		return m.Member.Email
+	case MemberFieldPhoneNumber:
+		return m.Member.PhoneNumber
+	case MemberFieldAddress:
+		return m.Member.Address
+	default:
+		return m.Member.Name
+	}
+}
+
+// highlightThreshold is the similarity above which a word is highlighted as a
+// fuzzy match of a query word. It matches the pg_trgm similarity threshold.
+const highlightThreshold = 0.3
+
+// Highlight wraps the parts of text that match query in <mark> tags. An exact,
+// case-insensitive occurrence of the query is preferred; otherwise the word of
+// text most similar to each query word is marked. The text is HTML-escaped, so
+// the result is safe to render as HTML.
+func Highlight(text, query string) string {
+	query = strings.TrimSpace(query)
+	lower := strings.ToLower(text)
+	if len(lower) == len(text) && query != "" {
+		if i := strings.Index(lower, strings.ToLower(query)); i >= 0 {
+			j := i + len(strings.ToLower(query))
+			return html.EscapeString(text[:i]) + "<mark>" + html.EscapeString(text[i:j]) + "</mark>" + html.EscapeString(text[j:])
+		}
+	}
+
+	words := wordSpans(text)
+	marked := make([]bool, len(words))
+	for _, q := range strings.FieldsFunc(strings.ToLower(query), isSeparator) {
+		best, bestScore := -1, highlightThreshold
+		for i, w := range words {
+			if score := similarity(q, strings.ToLower(text[w[0]:w[1]])); score >= bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best >= 0 {
+			marked[best] = true
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	for i, w := range words {
+		if !marked[i] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:w[0]]))
+		b.WriteString("<mark>" + html.EscapeString(text[w[0]:w[1]]) + "</mark>")
+		last = w[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// wordSpans returns the start and end offsets of the words of s.
+func wordSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s {
+		switch {
+		case !isSeparator(r) && start < 0:
+			start = i
+		case isSeparator(r) && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+// similarity returns the trigram similarity of two words the way pg_trgm
+// computes it: the shared trigrams over all distinct trigrams, with each word
+// padded by two spaces in front and one behind.
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	var shared int
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	total := len(ta) + len(tb) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
+
+func trigrams(word string) map[string]bool {
+	r := []rune("  " + word + " ")
+	set := make(map[string]bool, len(r))
+	for i := 0; i+3 <= len(r); i++ {
+		set[string(r[i:i+3])] = true
+	}
+	return set
+}
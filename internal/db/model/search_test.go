@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  string
+	}{
+		{"exact match", "Jane Smith", "smith", "Jane <mark>Smith</mark>"},
+		{"fuzzy match", "Jane Smith", "smyth", "Jane <mark>Smith</mark>"},
+		{"no match", "Jane Smith", "xyz", "Jane Smith"},
+		{"escapes exact match", "<b>Jane</b> & co", "jane", "&lt;b&gt;<mark>Jane</mark>&lt;/b&gt; &amp; co"},
+		{"escapes fuzzy match", `<img src=x onerror="alert(1)"> Smith`, "smyth", `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>Smith</mark>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, tt.query); got != tt.want {
+				t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxTagLength is the longest a member tag may be, in characters.
+const MaxTagLength = 50
+
+// NormalizeTag returns the tag as it is stored: trimmed, with runs of spaces
+// collapsed and in lower case, so "Competition  Squad" and "competition squad"
+// are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// ValidateTags returns a *ValidationError if any of the tags, which must already
+// be normalized, is invalid, or nil. field is the name of the tags in the API.
+func ValidateTags(field string, tags []string) error {
+	var verr ValidationError
+	validateTags(&verr, field, tags)
+	return verr.Err()
+}
+
+func validateTags(verr *ValidationError, field string, tags []string) {
+	for _, tag := range tags {
+		if tag == "" {
+			verr.Add(field, CodeRequired, "Tags must not be empty")
+			return
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			verr.Add(field, CodeOutOfRange, fmt.Sprintf("Tags must be at most %d characters", MaxTagLength))
+			return
+		}
+	}
+}
+
+// Segment is a saved selection of members, e.g. "unpaid last season". Its
+// members are those matching the filter at the time it is listed.
+type Segment struct {
+	ID        uuid.UUID     `db:"id"`
+	Name      string        `db:"name"`
+	Filter    SegmentFilter `db:"filter"`
+	CreatedAt time.Time     `db:"created_at"`
+}
+
+// SegmentFilter selects the members matching every condition that is set.
+//
+// SportID, MembershipStatus and Owing set to true select the members with a
+// membership matching all three; Owing set to false selects the members who owe
+// nothing on any membership. Cancelled memberships are never owing.
+type SegmentFilter struct {
+	Status           *MemberStatus     `json:"status,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	CustomFields     map[string]any    `json:"custom_fields,omitempty"`
+	JoinedAfter      *time.Time        `json:"joined_after,omitempty"`
+	JoinedBefore     *time.Time        `json:"joined_before,omitempty"`
+	SportID          *uuid.UUID        `json:"sport_id,omitempty"`
+	MembershipStatus *MembershipStatus `json:"membership_status,omitempty"`
+	Owing            *bool             `json:"owing,omitempty"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the segment,
+// or nil. Custom field values are checked against the definitions in fields.
+func (s *Segment) Validate(fields []*CustomField) error {
+	var verr ValidationError
+
+	switch {
+	case s.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case len(s.Name) <= 2:
+		verr.Add("name", CodeTooShort, "Name must be at least 3 characters")
+	}
+
+	f := s.Filter
+	if f.Status != nil {
+		if _, ok := MemberStatusMap[*f.Status]; !ok {
+			verr.Add("filter.status", CodeInvalidValue, "Status is not a known member status")
+		}
+	}
+	validateTags(&verr, "filter.tags", f.Tags)
+	validateCustomFieldValues(&verr, "filter.custom_fields.", f.CustomFields, fields)
+	if f.JoinedAfter != nil && f.JoinedBefore != nil && !f.JoinedAfter.Before(*f.JoinedBefore) {
+		verr.Add("filter.joined_before", CodeOutOfRange, "Joined before must be after joined after")
+	}
+	if f.MembershipStatus != nil {
+		if _, ok := MembershipStatusMap[*f.MembershipStatus]; !ok {
+			verr.Add("filter.membership_status", CodeInvalidValue, "Membership status is not a known membership status")
+		}
+	}
+
+	return verr.Err()
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmergencyContact is who to call when a member is hurt. The name and phone
+// number are encrypted at rest.
+type EmergencyContact struct {
+	ID           uuid.UUID `db:"id"`
+	MemberID     uuid.UUID `db:"member_id"`
+	Name         string    `db:"name"`
+	Relationship string    `db:"relationship"`
+	PhoneNumber  string    `db:"phone"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the contact, or nil.
+func (e *EmergencyContact) Validate() error {
+	var verr ValidationError
+
+	if e.Name == "" {
+		verr.Add("name", CodeRequired, "Name is required")
+	}
+	if e.Relationship == "" {
+		verr.Add("relationship", CodeRequired, "Relationship is required")
+	}
+	switch {
+	case e.PhoneNumber == "":
+		verr.Add("phone_number", CodeRequired, "Phone number is required")
+	case !ValidPhone(e.PhoneNumber):
+		verr.Add("phone_number", CodeInvalidFormat, "Phone number is not a valid phone number")
+	}
+
+	return verr.Err()
+}
+
+type MedicalCategory string
+
+var (
+	MedicalCategoryAllergy    MedicalCategory = "allergy"
+	MedicalCategoryCondition  MedicalCategory = "condition"
+	MedicalCategoryMedication MedicalCategory = "medication"
+	MedicalCategoryOther      MedicalCategory = "other"
+)
+
+// MedicalNote records a condition coaches need to know about, e.g. asthma. The
+// note is encrypted at rest.
+type MedicalNote struct {
+	ID        uuid.UUID       `db:"id"`
+	MemberID  uuid.UUID       `db:"member_id"`
+	Category  MedicalCategory `db:"category"`
+	Note      string          `db:"note"`
+	CreatedAt time.Time       `db:"created_at"`
+}
+
+// Validate returns a *ValidationError listing the invalid fields of the note, or nil.
+func (n *MedicalNote) Validate() error {
+	var verr ValidationError
+
+	switch n.Category {
+	case MedicalCategoryAllergy, MedicalCategoryCondition, MedicalCategoryMedication, MedicalCategoryOther:
+	case "":
+		verr.Add("category", CodeRequired, "Category is required")
+	default:
+		verr.Add("category", CodeInvalidValue, "Category must be allergy, condition, medication or other")
+	}
+	if n.Note == "" {
+		verr.Add("note", CodeRequired, "Note is required")
+	}
+
+	return verr.Err()
+}
+
+type SensitiveResource string
+
+var (
+	SensitiveResourceEmergencyContacts SensitiveResource = "emergency_contacts"
+	SensitiveResourceMedicalNotes      SensitiveResource = "medical_notes"
+)
+
+type SensitiveAction string
+
+var (
+	SensitiveActionRead   SensitiveAction = "read"
+	SensitiveActionCreate SensitiveAction = "create"
+	SensitiveActionDelete SensitiveAction = "delete"
+)
+
+// SensitiveAccess is an entry of the log of who accessed a member's sensitive data.
+type SensitiveAccess struct {
+	ID         int64             `db:"id"`
+	MemberID   uuid.UUID         `db:"member_id"`
+	Actor      string            `db:"actor"`
+	Resource   SensitiveResource `db:"resource"`
+	Action     SensitiveAction   `db:"action"`
+	AccessedAt time.Time         `db:"accessed_at"`
+}
@@ -1,16 +1,28 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Sport struct {
 	ID          uuid.UUID `db:"id"`
 	Name        string    `db:"name"`
 	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
 }
 
-func (s *Sport) Valid() bool {
-	if len(s.Name) <= 2 {
-		return false
+// Validate returns a *ValidationError listing the invalid fields of the sport, or nil.
+func (s *Sport) Validate() error {
+	var verr ValidationError
+
+	switch {
+	case s.Name == "":
+		verr.Add("name", CodeRequired, "Name is required")
+	case len(s.Name) <= 2:
+		verr.Add("name", CodeTooShort, "Name must be at least 3 characters")
 	}
-	return true
+
+	return verr.Err()
 }
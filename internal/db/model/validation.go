@@ -0,0 +1,50 @@
+package model
+
+import "strings"
+
+// Validation error codes, stable identifiers clients can match on.
+const (
+	CodeRequired      = "required"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidValue  = "invalid_value"
+	CodeTooShort      = "too_short"
+	CodeOutOfRange    = "out_of_range"
+)
+
+// FieldError describes why the value of a field is invalid. Field is the name
+// of the field in the API, e.g. "phone_number".
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Code
+}
+
+// ValidationError lists every invalid field of an entity.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+// Add records that field is invalid.
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err returns e if any field is invalid, and nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
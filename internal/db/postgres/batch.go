@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// currentMembershipStatuses are the statuses of the memberships taking a place in their batch.
+var currentMembershipStatuses = []model.MembershipStatus{
+	model.MembershipPendingPayment,
+	model.MembershipActive,
+	model.MembershipFrozen,
+	model.MembershipGrace,
+}
+
+type BatchStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewBatchStore(conn *pgxpool.Pool) *BatchStore {
+	return &BatchStore{
+		conn: conn,
+	}
+}
+
+// AddBatch adds the batch together with its weekly slots.
+func (s *BatchStore) AddBatch(ctx context.Context, batch *model.Batch) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO batches (sport_id, name, capacity, min_age, max_age, level)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	args := []any{
+		batch.SportID,
+		batch.Name,
+		batch.Capacity,
+		batch.MinAge,
+		batch.MaxAge,
+		batch.Level,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&batch.ID, &batch.CreatedAt); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrBatchAlreadyExists, err)
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrBatchReferenceNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add batch: %w", err)
+		}
+	}
+
+	if err := addBatchSlots(ctx, tx, batch); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to add batch: %w", err)
+	}
+	model.SortBatchSlots(batch.Slots)
+	return nil
+}
+
+// GetBatchByID returns the batch with its slots and the number of memberships enrolled in it.
+func (s *BatchStore) GetBatchByID(ctx context.Context, id uuid.UUID) (*model.Batch, error) {
+	batches, err := s.getBatches(ctx, "b.id = $2", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, ErrBatchNotFound
+	}
+	return batches[0], nil
+}
+
+// GetBatchesBySportID returns the batches of the sport by name, with their slots
+// and the number of memberships enrolled in each.
+func (s *BatchStore) GetBatchesBySportID(ctx context.Context, sportID uuid.UUID) ([]*model.Batch, error) {
+	return s.getBatches(ctx, "b.sport_id = $2", sportID)
+}
+
+func (s *BatchStore) getBatches(ctx context.Context, cond string, arg any) ([]*model.Batch, error) {
+	query := `
+		SELECT b.id, b.sport_id, b.name, b.capacity, b.min_age, b.max_age, b.level, b.created_at,
+			(SELECT count(*) FROM memberships ms WHERE ms.batch_id = b.id AND ms.status = ANY($1))
+		FROM batches b
+		WHERE ` + cond + `
+		ORDER BY b.name
+	`
+	rows, err := s.conn.Query(ctx, query, currentMembershipStatuses, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []*model.Batch
+	byID := make(map[uuid.UUID]*model.Batch)
+	for rows.Next() {
+		var batch model.Batch
+		if err := rows.Scan(
+			&batch.ID,
+			&batch.SportID,
+			&batch.Name,
+			&batch.Capacity,
+			&batch.MinAge,
+			&batch.MaxAge,
+			&batch.Level,
+			&batch.CreatedAt,
+			&batch.Enrolled,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan batch: %w", err)
+		}
+		batches = append(batches, &batch)
+		byID[batch.ID] = &batch
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over batches: %w", err)
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+
+	query = `
+		SELECT batch_id, weekday, EXTRACT(EPOCH FROM start_time)::INT / 60, EXTRACT(EPOCH FROM end_time)::INT / 60
+		FROM batch_slots
+		WHERE batch_id = ANY($1)
+		ORDER BY weekday, start_time
+	`
+	rows, err = s.conn.Query(ctx, query, keys(byID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch slots: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var batchID uuid.UUID
+		var slot model.BatchSlot
+		if err := rows.Scan(&batchID, &slot.Weekday, &slot.Start, &slot.End); err != nil {
+			return nil, fmt.Errorf("failed to scan batch slot: %w", err)
+		}
+		batch := byID[batchID]
+		batch.Slots = append(batch.Slots, slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over batch slots: %w", err)
+	}
+	return batches, nil
+}
+
+// UpdateBatch updates the batch and replaces its slots. Its sport cannot change.
+// It fails with ErrBatchCapacityBelowTotal if more memberships are enrolled than
+// the new capacity allows.
+func (s *BatchStore) UpdateBatch(ctx context.Context, batch *model.Batch) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	enrolled, err := lockBatch(ctx, tx, batch.ID)
+	if err != nil {
+		return err
+	}
+	if enrolled > batch.Capacity {
+		return ErrBatchCapacityBelowTotal
+	}
+
+	query := `
+		UPDATE batches
+		SET name = $1, capacity = $2, min_age = $3, max_age = $4, level = $5
+		WHERE id = $6
+		RETURNING sport_id, created_at
+	`
+	args := []any{
+		batch.Name,
+		batch.Capacity,
+		batch.MinAge,
+		batch.MaxAge,
+		batch.Level,
+		batch.ID,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&batch.SportID, &batch.CreatedAt); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrBatchAlreadyExists, err)
+		default:
+			return fmt.Errorf("failed to update batch: %w", err)
+		}
+	}
+
+	query = `
+		DELETE FROM batch_slots
+		WHERE batch_id = $1
+	`
+	if _, err := tx.Exec(ctx, query, batch.ID); err != nil {
+		return fmt.Errorf("failed to replace batch slots: %w", err)
+	}
+	if err := addBatchSlots(ctx, tx, batch); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update batch: %w", err)
+	}
+	batch.Enrolled = enrolled
+	model.SortBatchSlots(batch.Slots)
+	return nil
+}
+
+// DeleteBatch removes the batch. It fails with ErrBatchInUse while memberships,
+// current or past, refer to it.
+func (s *BatchStore) DeleteBatch(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM batches
+		WHERE id = $1
+	`
+	result, err := s.conn.Exec(ctx, query, id)
+	if err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrBatchInUse, err)
+		default:
+			return fmt.Errorf("failed to delete batch: %w", err)
+		}
+	}
+	if result.RowsAffected() == 0 {
+		return ErrBatchNotFound
+	}
+	return nil
+}
+
+func addBatchSlots(ctx context.Context, tx pgx.Tx, batch *model.Batch) error {
+	weekdays := make([]int16, len(batch.Slots))
+	starts := make([]int, len(batch.Slots))
+	ends := make([]int, len(batch.Slots))
+	for i, slot := range batch.Slots {
+		weekdays[i], starts[i], ends[i] = int16(slot.Weekday), int(slot.Start), int(slot.End)
+	}
+
+	query := `
+		INSERT INTO batch_slots (batch_id, weekday, start_time, end_time)
+		SELECT $1, s.weekday, TIME '00:00' + make_interval(mins => s.start_minute), TIME '00:00' + make_interval(mins => s.end_minute)
+		FROM unnest($2::SMALLINT[], $3::INT[], $4::INT[]) AS s(weekday, start_minute, end_minute)
+	`
+	if _, err := tx.Exec(ctx, query, batch.ID, weekdays, starts, ends); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrBatchAlreadyExists, err)
+		default:
+			return fmt.Errorf("failed to add batch slots: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockBatch locks the batch until the end of the transaction, so that enrolments
+// cannot race each other, and returns the number of memberships enrolled in it.
+// The lock does not conflict with the key share lock taken by writing a
+// membership that refers to the batch, so it may be taken after that write.
+func lockBatch(ctx context.Context, tx pgx.Tx, id uuid.UUID) (int, error) {
+	query := `
+		SELECT id
+		FROM batches
+		WHERE id = $1
+		FOR NO KEY UPDATE
+	`
+	if err := tx.QueryRow(ctx, query, id).Scan(&id); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return 0, fmt.Errorf("%w: %w", ErrBatchNotFound, err)
+		default:
+			return 0, fmt.Errorf("failed to lock batch: %w", err)
+		}
+	}
+
+	query = `
+		SELECT count(*)
+		FROM memberships
+		WHERE batch_id = $1 AND status = ANY($2)
+	`
+	var enrolled int
+	if err := tx.QueryRow(ctx, query, id, currentMembershipStatuses).Scan(&enrolled); err != nil {
+		return 0, fmt.Errorf("failed to count batch memberships: %w", err)
+	}
+	return enrolled, nil
+}
+
+// enrolInBatch checks that the membership, already written with its batch in the
+// transaction, may join it: the batch must be of the membership's sport and, if
+// the membership is current, not be over its capacity. The batch stays locked
+// until the end of the transaction.
+func enrolInBatch(ctx context.Context, tx pgx.Tx, membership *model.Membership) error {
+	enrolled, err := lockBatch(ctx, tx, *membership.BatchID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		SELECT sport_id, capacity
+		FROM batches
+		WHERE id = $1
+	`
+	var sportID uuid.UUID
+	var capacity int
+	if err := tx.QueryRow(ctx, query, *membership.BatchID).Scan(&sportID, &capacity); err != nil {
+		return fmt.Errorf("failed to get batch: %w", err)
+	}
+	if sportID != membership.SportID {
+		return ErrBatchSportMismatch
+	}
+	if slices.Contains(currentMembershipStatuses, membership.Status) && enrolled > capacity {
+		return ErrBatchFull
+	}
+	return nil
+}
+
+func keys[K comparable, V any](m map[K]V) []K {
+	ks := make([]K, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CustomFieldStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewCustomFieldStore(conn *pgxpool.Pool) *CustomFieldStore {
+	return &CustomFieldStore{
+		conn: conn,
+	}
+}
+
+func (s *CustomFieldStore) AddCustomField(ctx context.Context, field *model.CustomField) error {
+	query := `
+		INSERT INTO custom_fields (name, type, required, allowed_values)
+		VALUES ($1, $2, $3, COALESCE($4, '{}'::TEXT[]))
+		RETURNING id, name, type, required, allowed_values, created_at
+	`
+	args := []any{field.Name, field.Type, field.Required, field.AllowedValues}
+	if err := s.conn.QueryRow(ctx, query, args...).Scan(
+		&field.ID,
+		&field.Name,
+		&field.Type,
+		&field.Required,
+		&field.AllowedValues,
+		&field.CreatedAt,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrCustomFieldAlreadyExists, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add custom field: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *CustomFieldStore) GetCustomFieldByID(ctx context.Context, id uuid.UUID) (*model.CustomField, error) {
+	query := `
+		SELECT id, name, type, required, allowed_values, created_at
+		FROM custom_fields
+		WHERE id = $1
+	`
+	var field model.CustomField
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&field.ID,
+		&field.Name,
+		&field.Type,
+		&field.Required,
+		&field.AllowedValues,
+		&field.CreatedAt,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrCustomFieldNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get custom field: %w", err)
+		}
+	}
+	return &field, nil
+}
+
+// GetCustomFields returns every custom field definition, by name.
+func (s *CustomFieldStore) GetCustomFields(ctx context.Context) ([]*model.CustomField, error) {
+	query := `
+		SELECT id, name, type, required, allowed_values, created_at
+		FROM custom_fields
+		ORDER BY name
+	`
+	rows, err := s.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []*model.CustomField
+	for rows.Next() {
+		var field model.CustomField
+		if err := rows.Scan(
+			&field.ID,
+			&field.Name,
+			&field.Type,
+			&field.Required,
+			&field.AllowedValues,
+			&field.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, &field)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+// UpdateCustomField changes whether the field is required and its allowed values.
+// Its name and type are fixed, as the values on members depend on them.
+func (s *CustomFieldStore) UpdateCustomField(ctx context.Context, field *model.CustomField) error {
+	query := `
+		UPDATE custom_fields
+		SET required = $1, allowed_values = COALESCE($2, '{}'::TEXT[])
+		WHERE id = $3
+		RETURNING id, name, type, required, allowed_values, created_at
+	`
+	args := []any{field.Required, field.AllowedValues, field.ID}
+	if err := s.conn.QueryRow(ctx, query, args...).Scan(
+		&field.ID,
+		&field.Name,
+		&field.Type,
+		&field.Required,
+		&field.AllowedValues,
+		&field.CreatedAt,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrCustomFieldNotFound, err)
+		default:
+			return fmt.Errorf("failed to update custom field: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteCustomField removes the field and its values from every member.
+func (s *CustomFieldStore) DeleteCustomField(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		DELETE FROM custom_fields
+		WHERE id = $1
+		RETURNING name
+	`
+	var name string
+	if err := tx.QueryRow(ctx, query, id).Scan(&name); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrCustomFieldNotFound, err)
+		default:
+			return fmt.Errorf("failed to delete custom field: %w", err)
+		}
+	}
+
+	query = `
+		UPDATE members
+		SET custom_fields = custom_fields - $1
+		WHERE custom_fields ? $1
+	`
+	if _, err := tx.Exec(ctx, query, name); err != nil {
+		return fmt.Errorf("failed to remove custom field values: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	return nil
+}
@@ -10,12 +10,48 @@ var (
 	ErrMemberAlreadyExists  = errors.New("member already exists")
 	ErrMemberNotFound       = errors.New("member not found")
 	ErrMissingRequiredField = errors.New("missing required field")
+	ErrMergeConflict        = errors.New("members have conflicting memberships")
+	ErrEmailChangeNotFound  = errors.New("email change not found")
+	ErrMemberStatusChanged  = errors.New("member status changed")
 
 	ErrSportAlreadyExists = errors.New("sport already exists")
 	ErrSportNotFound      = errors.New("sport not found")
 
-	ErrMembershipAlreadyExists = errors.New("membership already exists")
-	ErrMembershipNotFound      = errors.New("membership not found")
+	ErrMembershipAlreadyExists     = errors.New("membership already exists")
+	ErrMembershipNotFound          = errors.New("membership not found")
+	ErrMembershipReferenceNotFound = errors.New("membership member or sport not found")
+	ErrMembershipStatusChanged     = errors.New("membership status changed")
+
+	ErrPaymentNotFound       = errors.New("payment not found")
+	ErrPaymentExceedsBalance = errors.New("payment exceeds the outstanding balance")
+
+	ErrPlanAlreadyExists = errors.New("plan already exists")
+	ErrPlanNotFound      = errors.New("plan not found")
+
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvoiceStatusChanged = errors.New("invoice status changed")
+
+	ErrHouseholdNotFound          = errors.New("household not found")
+	ErrHouseholdReferenceNotFound = errors.New("household or member not found")
+	ErrHouseholdMemberExists      = errors.New("member already belongs to a household")
+	ErrHouseholdMemberNotFound    = errors.New("household member not found")
+
+	ErrEmergencyContactNotFound = errors.New("emergency contact not found")
+	ErrMedicalNoteNotFound      = errors.New("medical note not found")
+
+	ErrCustomFieldAlreadyExists = errors.New("custom field already exists")
+	ErrCustomFieldNotFound      = errors.New("custom field not found")
+
+	ErrSegmentAlreadyExists = errors.New("segment already exists")
+	ErrSegmentNotFound      = errors.New("segment not found")
+
+	ErrBatchAlreadyExists      = errors.New("batch already exists")
+	ErrBatchNotFound           = errors.New("batch not found")
+	ErrBatchReferenceNotFound  = errors.New("batch sport not found")
+	ErrBatchFull               = errors.New("batch is full")
+	ErrBatchSportMismatch      = errors.New("batch is of another sport")
+	ErrBatchInUse              = errors.New("batch has memberships")
+	ErrBatchCapacityBelowTotal = errors.New("batch capacity below its enrolled memberships")
 )
 
 const (
@@ -23,8 +59,17 @@ const (
 	PgUniqueViolation     = "23505" // unique_violation
 	PgNotNullViolation    = "23502" // not_null_violation
 	PgForeignKeyViolation = "23503" // foreign_key_violation
+
+	membershipBatchConstraint = "memberships_batch_id_fkey"
 )
 
+// IsPgConstraintError checks if the error is a PostgreSQL error with the given code
+// on the named constraint
+func IsPgConstraintError(err error, code, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code && pgErr.ConstraintName == constraint
+}
+
 // IsPgError checks if the error is a PostgreSQL error with the given code
 func IsPgError(err error, code string) bool {
 	var pgErr *pgconn.PgError
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FreezeStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewFreezeStore(conn *pgxpool.Pool) *FreezeStore {
+	return &FreezeStore{
+		conn: conn,
+	}
+}
+
+func (s *FreezeStore) GetFreezesByMembershipID(ctx context.Context, membershipID uuid.UUID) ([]*model.Freeze, error) {
+	query := `
+		SELECT id, membership_id, start_date, end_date, days, reason
+		FROM membership_freezes
+		WHERE membership_id = $1
+		ORDER BY start_date
+	`
+	rows, err := s.conn.Query(ctx, query, membershipID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get freezes: %w", err)
+	}
+	defer rows.Close()
+
+	var freezes []*model.Freeze
+	for rows.Next() {
+		var freeze model.Freeze
+		if err := rows.Scan(
+			&freeze.ID,
+			&freeze.MembershipID,
+			&freeze.StartDate,
+			&freeze.EndDate,
+			&freeze.Days,
+			&freeze.Reason,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan freeze: %w", err)
+		}
+		freezes = append(freezes, &freeze)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over freezes: %w", err)
+	}
+	return freezes, nil
+}
+
+// FreezeMembership records the start of a freeze and applies the transition of the membership to frozen.
+func (s *FreezeStore) FreezeMembership(ctx context.Context, membership *model.Membership, freeze *model.Freeze, transition *model.MembershipTransition) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updateMembershipStatus(ctx, tx, transition); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO membership_freezes (membership_id, start_date, reason)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	if err := tx.QueryRow(ctx, query, membership.ID, freeze.StartDate, freeze.Reason).Scan(&freeze.ID); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrMembershipStatusChanged, err)
+		default:
+			return fmt.Errorf("failed to add freeze: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to freeze membership: %w", err)
+	}
+	freeze.MembershipID = membership.ID
+	membership.Status = transition.To
+	return nil
+}
+
+// UnfreezeMembership closes the freeze, extends the due date of the membership by
+// the credited days and applies the transition that reactivates it.
+func (s *FreezeStore) UnfreezeMembership(ctx context.Context, membership *model.Membership, freeze *model.Freeze, transition *model.MembershipTransition) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE membership_freezes
+		SET end_date = $1, days = $2
+		WHERE id = $3 AND end_date IS NULL
+	`
+	rows, err := tx.Exec(ctx, query, freeze.EndDate, freeze.Days, freeze.ID)
+	if err != nil {
+		return fmt.Errorf("failed to end freeze: %w", err)
+	}
+	if rows.RowsAffected() == 0 {
+		return ErrMembershipStatusChanged
+	}
+
+	query = `
+		UPDATE memberships
+		SET due_date = $1
+		WHERE id = $2
+	`
+	if _, err := tx.Exec(ctx, query, membership.DueDate, membership.ID); err != nil {
+		return fmt.Errorf("failed to extend membership: %w", err)
+	}
+
+	if err := updateMembershipStatus(ctx, tx, transition); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to unfreeze membership: %w", err)
+	}
+	membership.Status = transition.To
+	return nil
+}
@@ -0,0 +1,322 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HouseholdStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewHouseholdStore(conn *pgxpool.Pool) *HouseholdStore {
+	return &HouseholdStore{
+		conn: conn,
+	}
+}
+
+// AddHousehold adds the household together with its members.
+func (s *HouseholdStore) AddHousehold(ctx context.Context, household *model.Household) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO households (name)
+		VALUES ($1)
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRow(ctx, query, household.Name).Scan(&household.ID, &household.CreatedAt); err != nil {
+		switch {
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add household: %w", err)
+		}
+	}
+
+	for _, member := range household.Members {
+		member.HouseholdID = household.ID
+		if err := addHouseholdMember(ctx, tx, member); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *HouseholdStore) GetHouseholdByID(ctx context.Context, id uuid.UUID) (*model.Household, error) {
+	query := `
+		SELECT id, name, created_at
+		FROM households
+		WHERE id = $1
+	`
+	var household model.Household
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&household.ID,
+		&household.Name,
+		&household.CreatedAt,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrHouseholdNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get household: %w", err)
+		}
+	}
+
+	query = `
+		SELECT hm.household_id, hm.member_id, m.name, hm.relationship, hm.is_primary
+		FROM household_members hm
+		JOIN members m ON m.id = hm.member_id
+		WHERE hm.household_id = $1
+		ORDER BY hm.is_primary DESC, m.name
+	`
+	rows, err := s.conn.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get household members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member model.HouseholdMember
+		if err := rows.Scan(
+			&member.HouseholdID,
+			&member.MemberID,
+			&member.Name,
+			&member.Relationship,
+			&member.Primary,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan household member: %w", err)
+		}
+		household.Members = append(household.Members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over household members: %w", err)
+	}
+	return &household, nil
+}
+
+// GetHouseholdByMemberID returns the household the member belongs to.
+func (s *HouseholdStore) GetHouseholdByMemberID(ctx context.Context, memberID uuid.UUID) (*model.Household, error) {
+	query := `
+		SELECT household_id
+		FROM household_members
+		WHERE member_id = $1
+	`
+	var id uuid.UUID
+	if err := s.conn.QueryRow(ctx, query, memberID).Scan(&id); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrHouseholdNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get household: %w", err)
+		}
+	}
+	return s.GetHouseholdByID(ctx, id)
+}
+
+func (s *HouseholdStore) DeleteHousehold(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM households
+		WHERE id = $1
+	`
+	result, err := s.conn.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete household: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrHouseholdNotFound
+	}
+	return nil
+}
+
+func (s *HouseholdStore) AddHouseholdMember(ctx context.Context, member *model.HouseholdMember) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := addHouseholdMember(ctx, tx, member); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func addHouseholdMember(ctx context.Context, tx pgx.Tx, member *model.HouseholdMember) error {
+	query := `
+		INSERT INTO household_members (household_id, member_id, relationship, is_primary)
+		VALUES ($1, $2, $3, $4)
+		RETURNING (SELECT name FROM members WHERE id = $2)
+	`
+	args := []any{
+		member.HouseholdID,
+		member.MemberID,
+		member.Relationship,
+		member.Primary,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&member.Name); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrHouseholdMemberExists, err)
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrHouseholdReferenceNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add household member: %w", err)
+		}
+	}
+	return nil
+}
+
+// RemoveHouseholdMember removes the member from the household. The primary payer
+// cannot be removed; another member has to be made primary first.
+func (s *HouseholdStore) RemoveHouseholdMember(ctx context.Context, householdID, memberID uuid.UUID) error {
+	query := `
+		DELETE FROM household_members
+		WHERE household_id = $1 AND member_id = $2 AND NOT is_primary
+	`
+	result, err := s.conn.Exec(ctx, query, householdID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to remove household member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrHouseholdMemberNotFound
+	}
+	return nil
+}
+
+// SetHouseholdPrimary makes the member the primary payer of the household in place of the current one.
+func (s *HouseholdStore) SetHouseholdPrimary(ctx context.Context, householdID, memberID uuid.UUID) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE household_members
+		SET is_primary = FALSE
+		WHERE household_id = $1 AND is_primary
+	`
+	if _, err := tx.Exec(ctx, query, householdID); err != nil {
+		return fmt.Errorf("failed to unset household primary: %w", err)
+	}
+
+	query = `
+		UPDATE household_members
+		SET is_primary = TRUE
+		WHERE household_id = $1 AND member_id = $2
+	`
+	result, err := tx.Exec(ctx, query, householdID, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to set household primary: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrHouseholdMemberNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetHouseholdMemberships returns the memberships of every member of the household.
+func (s *HouseholdStore) GetHouseholdMemberships(ctx context.Context, householdID uuid.UUID) ([]*model.Membership, error) {
+	query := `
+		SELECT ms.id, ms.member_id, ms.sport_id, ms.plan_id, ms.type, ms.start_date, ms.due_date, ms.status, ms.currency, ms.fees, ms.auto_renew, ms.batch_id
+		FROM memberships ms
+		JOIN household_members hm ON hm.member_id = ms.member_id
+		WHERE hm.household_id = $1
+		ORDER BY ms.member_id, ms.start_date
+	`
+	rows, err := s.conn.Query(ctx, query, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get household memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []*model.Membership
+	for rows.Next() {
+		var membership model.Membership
+		if err := rows.Scan(
+			&membership.ID,
+			&membership.MemberID,
+			&membership.SportID,
+			&membership.PlanID,
+			&membership.Type,
+			&membership.StartDate,
+			&membership.DueDate,
+			&membership.Status,
+			&membership.Fee.Currency,
+			&membership.Fee,
+			&membership.AutoRenew,
+			&membership.BatchID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, &membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// GetHouseholdDues returns the memberships of the household's members with an
+// unpaid balance, counted the same way as GetOutstandingDues.
+func (s *HouseholdStore) GetHouseholdDues(ctx context.Context, householdID uuid.UUID) ([]*model.Due, error) {
+	query := `
+		SELECT m.id, m.name, m.email, m.phone, ms.id, ms.sport_id, s.name, ms.due_date,
+			ms.currency, ms.fees,
+			ms.currency, COALESCE(SUM(p.amount) FILTER (WHERE p.status = $1 AND p.currency = ms.currency), 0)
+		FROM memberships ms
+		JOIN household_members hm ON hm.member_id = ms.member_id
+		JOIN members m ON m.id = ms.member_id
+		JOIN sports s ON s.id = ms.sport_id
+		LEFT JOIN payments p ON p.membership_id = ms.id
+		WHERE hm.household_id = $2 AND ms.status <> $3 AND m.deleted_at IS NULL
+		GROUP BY m.id, ms.id, s.id
+		HAVING ms.fees > COALESCE(SUM(p.amount) FILTER (WHERE p.status = $1 AND p.currency = ms.currency), 0)
+		ORDER BY ms.due_date, m.name
+	`
+	rows, err := s.conn.Query(ctx, query, model.PaymentStatusCompleted, householdID, model.MembershipCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get household dues: %w", err)
+	}
+	defer rows.Close()
+
+	var dues []*model.Due
+	for rows.Next() {
+		var due model.Due
+		if err := rows.Scan(
+			&due.MemberID,
+			&due.MemberName,
+			&due.Email,
+			&due.PhoneNumber,
+			&due.MembershipID,
+			&due.SportID,
+			&due.SportName,
+			&due.DueDate,
+			&due.Fee.Currency,
+			&due.Fee,
+			&due.Paid.Currency,
+			&due.Paid,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan due: %w", err)
+		}
+		dues = append(dues, &due)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over dues: %w", err)
+	}
+	return dues, nil
+}
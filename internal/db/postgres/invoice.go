@@ -0,0 +1,359 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type InvoiceStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewInvoiceStore(conn *pgxpool.Pool) *InvoiceStore {
+	return &InvoiceStore{
+		conn: conn,
+	}
+}
+
+// AddInvoice adds a draft invoice together with its line items. The credit
+// balances in the currency of the invoice of the members whose memberships are
+// invoiced, e.g. the children of a household billed to its payer, and of the
+// billed member are applied to it, in that order.
+func (s *InvoiceStore) AddInvoice(ctx context.Context, invoice *model.Invoice) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	memberIDs, err := invoiceCreditMembers(ctx, tx, invoice)
+	if err != nil {
+		return err
+	}
+	balances, err := lockMembersCredit(ctx, tx, memberIDs, invoice.Total.Currency)
+	if err != nil {
+		return err
+	}
+	balance := model.Money{Currency: invoice.Total.Currency}
+	for _, memberBalance := range balances {
+		if memberBalance.IsPositive() {
+			balance = balance.Add(memberBalance)
+		}
+	}
+	applied := invoice.ApplyCredit(balance)
+
+	query := `
+		INSERT INTO invoices (member_id, status, currency, total)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	args := []any{
+		invoice.MemberID,
+		invoice.Status,
+		invoice.Total.Currency,
+		invoice.Total,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(
+		&invoice.ID,
+		&invoice.CreatedAt,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add invoice: %w", err)
+		}
+	}
+
+	query = `
+		INSERT INTO invoice_items (invoice_id, membership_id, description, currency, amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	for _, item := range invoice.Items {
+		item.InvoiceID = invoice.ID
+		args := []any{
+			item.InvoiceID,
+			item.MembershipID,
+			item.Description,
+			item.Amount.Currency,
+			item.Amount,
+		}
+		if err := tx.QueryRow(ctx, query, args...).Scan(&item.ID); err != nil {
+			switch {
+			case IsPgError(err, PgForeignKeyViolation):
+				return fmt.Errorf("%w: %w", ErrMembershipNotFound, err)
+			default:
+				return fmt.Errorf("failed to add invoice item: %w", err)
+			}
+		}
+	}
+
+	// Each member's ledger records what was drawn from their credit.
+	for _, memberID := range memberIDs {
+		drawn := min(applied.Amount, balances[memberID].Amount)
+		if drawn <= 0 {
+			continue
+		}
+		if err := addMemberCredit(ctx, tx, &model.MemberCredit{
+			MemberID:  memberID,
+			Amount:    model.Money{Amount: -drawn, Currency: applied.Currency},
+			InvoiceID: &invoice.ID,
+			Reason:    "applied to invoice",
+		}); err != nil {
+			return err
+		}
+		applied.Amount -= drawn
+	}
+
+	return tx.Commit(ctx)
+}
+
+// invoiceCreditMembers returns the members whose credit is applied to the
+// invoice: the members of the invoiced memberships, in the order of the items,
+// followed by the billed member.
+func invoiceCreditMembers(ctx context.Context, tx pgx.Tx, invoice *model.Invoice) ([]uuid.UUID, error) {
+	var membershipIDs []uuid.UUID
+	for _, item := range invoice.Items {
+		if item.MembershipID != nil {
+			membershipIDs = append(membershipIDs, *item.MembershipID)
+		}
+	}
+
+	query := `
+		SELECT id, member_id
+		FROM memberships
+		WHERE id = ANY($1)
+	`
+	rows, err := tx.Query(ctx, query, membershipIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoiced memberships: %w", err)
+	}
+	defer rows.Close()
+
+	owners := make(map[uuid.UUID]uuid.UUID, len(membershipIDs))
+	for rows.Next() {
+		var membershipID, memberID uuid.UUID
+		if err := rows.Scan(&membershipID, &memberID); err != nil {
+			return nil, fmt.Errorf("failed to scan invoiced membership: %w", err)
+		}
+		owners[membershipID] = memberID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over invoiced memberships: %w", err)
+	}
+
+	var memberIDs []uuid.UUID
+	for _, membershipID := range membershipIDs {
+		if memberID, ok := owners[membershipID]; ok && !slices.Contains(memberIDs, memberID) {
+			memberIDs = append(memberIDs, memberID)
+		}
+	}
+	if !slices.Contains(memberIDs, invoice.MemberID) {
+		memberIDs = append(memberIDs, invoice.MemberID)
+	}
+	return memberIDs, nil
+}
+
+func (s *InvoiceStore) GetInvoiceByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error) {
+	query := `
+		SELECT id, COALESCE(number, ''), member_id, status, currency, total, created_at, issued_at
+		FROM invoices
+		WHERE id = $1
+	`
+	var invoice model.Invoice
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&invoice.ID,
+		&invoice.Number,
+		&invoice.MemberID,
+		&invoice.Status,
+		&invoice.Total.Currency,
+		&invoice.Total,
+		&invoice.CreatedAt,
+		&invoice.IssuedAt,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrInvoiceNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get invoice: %w", err)
+		}
+	}
+
+	query = `
+		SELECT id, invoice_id, membership_id, description, currency, amount
+		FROM invoice_items
+		WHERE invoice_id = $1
+		ORDER BY description
+	`
+	rows, err := s.conn.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.InvoiceItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.InvoiceID,
+			&item.MembershipID,
+			&item.Description,
+			&item.Amount.Currency,
+			&item.Amount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice item: %w", err)
+		}
+		invoice.Items = append(invoice.Items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over invoice items: %w", err)
+	}
+	return &invoice, nil
+}
+
+// GetInvoicesByMemberID returns the member's invoices without their line items.
+func (s *InvoiceStore) GetInvoicesByMemberID(ctx context.Context, memberID uuid.UUID) ([]*model.Invoice, error) {
+	query := `
+		SELECT id, COALESCE(number, ''), member_id, status, currency, total, created_at, issued_at
+		FROM invoices
+		WHERE member_id = $1
+		ORDER BY created_at
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []*model.Invoice
+	for rows.Next() {
+		var invoice model.Invoice
+		if err := rows.Scan(
+			&invoice.ID,
+			&invoice.Number,
+			&invoice.MemberID,
+			&invoice.Status,
+			&invoice.Total.Currency,
+			&invoice.Total,
+			&invoice.CreatedAt,
+			&invoice.IssuedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, &invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// IssueInvoice allocates the next invoice number for the prefix and the current
+// year and marks the draft invoice as issued. The number is allocated in the same
+// transaction as the status change, so numbers are never skipped.
+func (s *InvoiceStore) IssueInvoice(ctx context.Context, invoice *model.Invoice, prefix string, now time.Time) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status model.InvoiceStatus
+	query := `
+		SELECT status
+		FROM invoices
+		WHERE id = $1
+		FOR UPDATE
+	`
+	if err := tx.QueryRow(ctx, query, invoice.ID).Scan(&status); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrInvoiceNotFound, err)
+		default:
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+	}
+	if status != model.InvoiceStatusDraft {
+		return ErrInvoiceStatusChanged
+	}
+
+	var sequence int
+	query = `
+		INSERT INTO invoice_sequences (prefix, year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (prefix, year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`
+	if err := tx.QueryRow(ctx, query, prefix, now.Year()).Scan(&sequence); err != nil {
+		return fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+
+	query = `
+		UPDATE invoices
+		SET number = $1, status = $2, issued_at = $3
+		WHERE id = $4
+	`
+	number := model.InvoiceNumber(prefix, now.Year(), sequence)
+	if _, err := tx.Exec(ctx, query, number, model.InvoiceStatusIssued, now, invoice.ID); err != nil {
+		return fmt.Errorf("failed to issue invoice: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to issue invoice: %w", err)
+	}
+
+	invoice.Number = number
+	invoice.Status = model.InvoiceStatusIssued
+	invoice.IssuedAt = &now
+	return nil
+}
+
+// UpdateInvoiceStatus moves the invoice to status, provided it still has the status it was read with.
+// Voiding the invoice gives back the credit applied to it.
+func (s *InvoiceStore) UpdateInvoiceStatus(ctx context.Context, invoice *model.Invoice, status model.InvoiceStatus) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE invoices
+		SET status = $1
+		WHERE id = $2 AND status = $3
+	`
+	rows, err := tx.Exec(ctx, query, status, invoice.ID, invoice.Status)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice status: %w", err)
+	}
+	if rows.RowsAffected() == 0 {
+		return ErrInvoiceStatusChanged
+	}
+
+	if status == model.InvoiceStatusVoid {
+		query = `
+			DELETE FROM member_credits
+			WHERE invoice_id = $1
+		`
+		if _, err := tx.Exec(ctx, query, invoice.ID); err != nil {
+			return fmt.Errorf("failed to give back member credit: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update invoice status: %w", err)
+	}
+	invoice.Status = status
+	return nil
+}
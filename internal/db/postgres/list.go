@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+)
+
+var sortColumns = map[model.SortField]string{
+	model.SortName:    "name",
+	model.SortCreated: "created_at",
+}
+
+// paginate returns the WHERE, ORDER BY and LIMIT clauses selecting the page of
+// opts from the rows matching conds, together with the query arguments. The ID
+// breaks ties between equal sort keys. One row more than the limit is selected
+// so that nextCursor can tell whether there is a next page.
+func paginate(opts model.ListOptions, conds []string, args []any) (string, []any, error) {
+	column, ok := sortColumns[opts.Sort]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown sort field %q", opts.Sort)
+	}
+	op, dir := ">", "ASC"
+	if opts.Order == model.SortDesc {
+		op, dir = "<", "DESC"
+	}
+
+	if opts.After != nil {
+		if opts.After.Sort != opts.Sort || opts.After.Order != opts.Order {
+			return "", nil, model.ErrInvalidCursor
+		}
+		var key any = opts.After.Key
+		if opts.Sort == model.SortCreated {
+			t, err := time.Parse(time.RFC3339Nano, opts.After.Key)
+			if err != nil {
+				return "", nil, model.ErrInvalidCursor
+			}
+			key = t
+		}
+		args = append(args, key, opts.After.ID)
+		conds = append(conds, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, op, len(args)-1, len(args)))
+	}
+
+	var clause string
+	if len(conds) > 0 {
+		clause = "WHERE " + strings.Join(conds, " AND ") + "\n"
+	}
+	args = append(args, opts.Limit+1)
+	clause += fmt.Sprintf("ORDER BY %s %s, id %s\nLIMIT $%d", column, dir, dir, len(args))
+	return clause, args, nil
+}
+
+// nextCursor returns the cursor pointing after the last row of a page.
+func nextCursor(opts model.ListOptions, name string, createdAt time.Time, id uuid.UUID) *model.Cursor {
+	key := name
+	if opts.Sort == model.SortCreated {
+		key = createdAt.Format(time.RFC3339Nano)
+	}
+	return &model.Cursor{Sort: opts.Sort, Order: opts.Order, Key: key, ID: id}
+}
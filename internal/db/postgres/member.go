@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
 	"github.com/google/uuid"
@@ -23,16 +24,18 @@ func NewMemberStore(conn *pgxpool.Pool) *MemberStore {
 
 func (s *MemberStore) AddMember(ctx context.Context, member *model.Member) error {
 	query := `
-		INSERT INTO members (name, email, phone, address, join_date, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING *
+		INSERT INTO members (name, email, phone, address, join_date, status, custom_fields)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, '{}'))
+		RETURNING id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
 	`
 	args := []any{
 		member.Name,
 		member.Email,
 		member.PhoneNumber,
 		member.Address,
+		member.JoinDate,
 		member.Status,
+		member.CustomFields,
 	}
 
 	if err := s.conn.QueryRow(ctx, query, args...).Scan(
@@ -41,7 +44,12 @@ func (s *MemberStore) AddMember(ctx context.Context, member *model.Member) error
 		&member.Email,
 		&member.PhoneNumber,
 		&member.Address,
+		&member.JoinDate,
 		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
 	); err != nil {
 		switch {
 		case IsPgError(err, PgUniqueViolation):
@@ -57,7 +65,7 @@ func (s *MemberStore) AddMember(ctx context.Context, member *model.Member) error
 
 func (s *MemberStore) GetMemberByID(ctx context.Context, id uuid.UUID) (*model.Member, error) {
 	query := `
-		SELECT id, name, email, phone, address, join_date, status
+		SELECT id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
 		FROM members
 		WHERE id = $1
 	`
@@ -68,7 +76,12 @@ func (s *MemberStore) GetMemberByID(ctx context.Context, id uuid.UUID) (*model.M
 		&member.Email,
 		&member.PhoneNumber,
 		&member.Address,
+		&member.JoinDate,
 		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
 	); err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
@@ -80,11 +93,12 @@ func (s *MemberStore) GetMemberByID(ctx context.Context, id uuid.UUID) (*model.M
 	return &member, nil
 }
 
+// GetMemberByEmail returns the current member with the email, ignoring case.
 func (s *MemberStore) GetMemberByEmail(ctx context.Context, email string) (*model.Member, error) {
 	query := `
-		SELECT id, name, email, phone, address, join_date, status
+		SELECT id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
 		FROM members
-		WHERE email = $1
+		WHERE lower(email) = lower($1) AND deleted_at IS NULL
 	`
 	var member model.Member
 	if err := s.conn.QueryRow(ctx, query, email).Scan(
@@ -93,7 +107,12 @@ func (s *MemberStore) GetMemberByEmail(ctx context.Context, email string) (*mode
 		&member.Email,
 		&member.PhoneNumber,
 		&member.Address,
+		&member.JoinDate,
 		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
 	); err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
@@ -105,14 +124,126 @@ func (s *MemberStore) GetMemberByEmail(ctx context.Context, email string) (*mode
 	return &member, nil
 }
 
-func (s *MemberStore) GetAllMembers(ctx context.Context) ([]*model.Member, error) {
+// GetMemberByPhone returns the current member with the phone number, which must be in E.164 form.
+func (s *MemberStore) GetMemberByPhone(ctx context.Context, phone string) (*model.Member, error) {
 	query := `
-		SELECT id, name, email, phone, address, join_date, status
+		SELECT id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
+		FROM members
+		WHERE phone = $1 AND deleted_at IS NULL
+	`
+	var member model.Member
+	if err := s.conn.QueryRow(ctx, query, phone).Scan(
+		&member.ID,
+		&member.Name,
+		&member.Email,
+		&member.PhoneNumber,
+		&member.Address,
+		&member.JoinDate,
+		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get member: %w", err)
+		}
+	}
+	return &member, nil
+}
+
+// GetMemberPhones returns the phone number of every member, archived or not, by member ID.
+func (s *MemberStore) GetMemberPhones(ctx context.Context) (map[uuid.UUID]string, error) {
+	query := `
+		SELECT id, phone
 		FROM members
 	`
 	rows, err := s.conn.Query(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get members: %w", err)
+		return nil, fmt.Errorf("failed to get member phones: %w", err)
+	}
+	defer rows.Close()
+
+	phones := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var id uuid.UUID
+		var phone string
+		if err := rows.Scan(&id, &phone); err != nil {
+			return nil, fmt.Errorf("failed to scan member phone: %w", err)
+		}
+		phones[id] = phone
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over member phones: %w", err)
+	}
+	return phones, nil
+}
+
+// UpdateMemberPhones sets the phone numbers of the members, by member ID, in a single transaction.
+func (s *MemberStore) UpdateMemberPhones(ctx context.Context, phones map[uuid.UUID]string) (int64, error) {
+	ids := make([]uuid.UUID, 0, len(phones))
+	values := make([]string, 0, len(phones))
+	for id, phone := range phones {
+		ids = append(ids, id)
+		values = append(values, phone)
+	}
+
+	query := `
+		UPDATE members m
+		SET phone = p.phone
+		FROM unnest($1::UUID[], $2::TEXT[]) AS p(id, phone)
+		WHERE m.id = p.id
+	`
+	result, err := s.conn.Exec(ctx, query, ids, values)
+	if err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return 0, fmt.Errorf("%w: %w", ErrMemberAlreadyExists, err)
+		default:
+			return 0, fmt.Errorf("failed to update member phones: %w", err)
+		}
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListMembers returns the page of members selected by opts, and the cursor of the
+// next page or nil if this is the last one.
+func (s *MemberStore) ListMembers(ctx context.Context, opts model.MemberListOptions) ([]*model.Member, *model.Cursor, error) {
+	conds := []string{"deleted_at IS NULL"}
+	if opts.Archived {
+		conds[0] = "deleted_at IS NOT NULL"
+	}
+	var args []any
+	if opts.Status != nil {
+		args = append(args, *opts.Status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if len(opts.CustomFields) > 0 {
+		args = append(args, opts.CustomFields)
+		conds = append(conds, fmt.Sprintf("custom_fields @> $%d::JSONB", len(args)))
+	}
+	if len(opts.Tags) > 0 {
+		args = append(args, opts.Tags)
+		conds = append(conds, tagsCond(fmt.Sprintf("$%d", len(args))))
+	}
+	if opts.Segment != nil {
+		conds, args = segmentConds(opts.Segment, conds, args)
+	}
+	clause, args, err := paginate(opts.ListOptions, conds, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+		SELECT id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
+		FROM members
+	` + clause
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get members: %w", err)
 	}
 	defer rows.Close()
 
@@ -125,7 +256,121 @@ func (s *MemberStore) GetAllMembers(ctx context.Context) ([]*model.Member, error
 			&member.Email,
 			&member.PhoneNumber,
 			&member.Address,
+			&member.JoinDate,
+			&member.Status,
+			&member.CreatedAt,
+			&member.DeletedAt,
+			&member.PhotoUpdatedAt,
+			&member.CustomFields,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate over members: %w", err)
+	}
+
+	if len(members) <= opts.Limit {
+		return members, nil, nil
+	}
+	members = members[:opts.Limit]
+	last := members[len(members)-1]
+	return members, nextCursor(opts.ListOptions, last.Name, last.CreatedAt, last.ID), nil
+}
+
+// SearchMembers returns up to limit members whose name, email, phone or address
+// resembles query, best matches first. Matching uses trigram word similarity, so
+// it tolerates typos and partial input.
+func (s *MemberStore) SearchMembers(ctx context.Context, query string, limit int) ([]*model.MemberMatch, error) {
+	sql := `
+		SELECT id, name, email, phone, address, join_date, status, created_at, deleted_at, photo_updated_at, custom_fields,
+			word_similarity($1, name), word_similarity($1, email),
+			word_similarity($1, phone), word_similarity($1, address)
+		FROM (
+			SELECT id, name, email, phone, COALESCE(address, '') AS address, join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
+			FROM members
+			WHERE ($1 <% name OR $1 <% email OR $1 <% phone OR $1 <% address) AND deleted_at IS NULL
+		) m
+		ORDER BY GREATEST(word_similarity($1, name), word_similarity($1, email),
+			word_similarity($1, phone), word_similarity($1, address)) DESC, name, id
+		LIMIT $2
+	`
+	rows, err := s.conn.Query(ctx, sql, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search members: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*model.MemberMatch
+	for rows.Next() {
+		var member model.Member
+		var scores [4]float64
+		if err := rows.Scan(
+			&member.ID,
+			&member.Name,
+			&member.Email,
+			&member.PhoneNumber,
+			&member.Address,
+			&member.JoinDate,
 			&member.Status,
+			&member.CreatedAt,
+			&member.DeletedAt,
+			&member.PhotoUpdatedAt,
+			&member.CustomFields,
+			&scores[0],
+			&scores[1],
+			&scores[2],
+			&scores[3],
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+
+		match := &model.MemberMatch{Member: &member}
+		fields := []string{model.MemberFieldName, model.MemberFieldEmail, model.MemberFieldPhoneNumber, model.MemberFieldAddress}
+		for i, score := range scores {
+			if score > match.Score {
+				match.Field, match.Score = fields[i], score
+			}
+		}
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over members: %w", err)
+	}
+	return matches, nil
+}
+
+// GetMemberAnniversaries returns the members who joined in month of any year
+// before year, longest-standing first.
+func (s *MemberStore) GetMemberAnniversaries(ctx context.Context, month time.Month, year int) ([]*model.Member, error) {
+	query := `
+		SELECT id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
+		FROM members
+		WHERE EXTRACT(MONTH FROM join_date) = $1 AND EXTRACT(YEAR FROM join_date) < $2 AND deleted_at IS NULL
+		ORDER BY join_date, name
+	`
+	rows, err := s.conn.Query(ctx, query, int(month), year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member anniversaries: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*model.Member
+	for rows.Next() {
+		var member model.Member
+		if err := rows.Scan(
+			&member.ID,
+			&member.Name,
+			&member.Email,
+			&member.PhoneNumber,
+			&member.Address,
+			&member.JoinDate,
+			&member.Status,
+			&member.CreatedAt,
+			&member.DeletedAt,
+			&member.PhotoUpdatedAt,
+			&member.CustomFields,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan member: %w", err)
 		}
@@ -137,28 +382,50 @@ func (s *MemberStore) GetAllMembers(ctx context.Context) ([]*model.Member, error
 	return members, nil
 }
 
-func (s *MemberStore) UpdateMember(ctx context.Context, member *model.Member) error {
+// UpdateMember updates the member. When change is not nil the member's status
+// changes with it and the change is recorded in the status history; it fails with
+// ErrMemberStatusChanged if the member no longer has the status change is from.
+func (s *MemberStore) UpdateMember(ctx context.Context, member *model.Member, change *model.MemberStatusChange) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if change != nil {
+		if err := lockMemberStatus(ctx, tx, member.ID, change.From); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE members
-		SET name = $1, email = $2, phone = $3, address = $4, join_date = $5, status = $6
+		SET name = $1, email = $2, phone = $3, address = $4, join_date = $5, status = $6, custom_fields = COALESCE($8, '{}')
 		WHERE id = $7
-		RETURNING *
+		RETURNING id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
 	`
 	args := []any{
 		member.Name,
 		member.Email,
 		member.PhoneNumber,
 		member.Address,
+		member.JoinDate,
 		member.Status,
 		member.ID,
+		member.CustomFields,
 	}
-	err := s.conn.QueryRow(ctx, query, args...).Scan(
+	err = tx.QueryRow(ctx, query, args...).Scan(
 		&member.ID,
 		&member.Name,
 		&member.Email,
 		&member.PhoneNumber,
 		&member.Address,
+		&member.JoinDate,
 		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
 	)
 	if err != nil {
 		switch {
@@ -172,20 +439,161 @@ func (s *MemberStore) UpdateMember(ctx context.Context, member *model.Member) er
 			return fmt.Errorf("failed to update member: %w", err)
 		}
 	}
+
+	if change != nil {
+		if err := addMemberStatusChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
 	return nil
 }
 
+// DeleteMember archives the member. Archived members and their memberships are
+// kept, but hidden from listings and search, until PurgeMembers removes them.
+// Their memberships stop renewing and are left alone by the membership worker.
 func (s *MemberStore) DeleteMember(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		DELETE FROM members
-		WHERE id = $1
+		UPDATE members
+		SET deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
 	`
-	rows, err := s.conn.Exec(ctx, query, id)
+	rows, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete member: %w", err)
+	}
 	if rows.RowsAffected() == 0 {
-		return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		return ErrMemberNotFound
 	}
-	if err != nil {
+
+	query = `
+		UPDATE memberships
+		SET auto_renew = FALSE
+		WHERE member_id = $1 AND auto_renew
+	`
+	if _, err := tx.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to stop membership renewals: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to delete member: %w", err)
 	}
 	return nil
 }
+
+// RestoreMember brings back an archived member.
+func (s *MemberStore) RestoreMember(ctx context.Context, id uuid.UUID) (*model.Member, error) {
+	query := `
+		UPDATE members
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
+	`
+	var member model.Member
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&member.ID,
+		&member.Name,
+		&member.Email,
+		&member.PhoneNumber,
+		&member.Address,
+		&member.JoinDate,
+		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		case IsPgError(err, PgUniqueViolation):
+			return nil, fmt.Errorf("%w: %w", ErrMemberAlreadyExists, err)
+		default:
+			return nil, fmt.Errorf("failed to restore member: %w", err)
+		}
+	}
+	return &member, nil
+}
+
+// SetMemberPhoto records when the member's photo was uploaded, or that they have
+// none when updatedAt is nil.
+func (s *MemberStore) SetMemberPhoto(ctx context.Context, id uuid.UUID, updatedAt *time.Time) error {
+	query := `
+		UPDATE members
+		SET photo_updated_at = $2
+		WHERE id = $1
+	`
+	result, err := s.conn.Exec(ctx, query, id, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set member photo: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// PurgeMembers permanently removes the members archived before archivedBefore,
+// together with their memberships, payments and draft invoices, and returns
+// their IDs. Members with issued invoices are kept, as invoices must be retained.
+func (s *MemberStore) PurgeMembers(ctx context.Context, archivedBefore time.Time) ([]uuid.UUID, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id
+		FROM members m
+		WHERE deleted_at < $1
+			AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.member_id = m.id AND i.status <> $2)
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query, archivedBefore, model.InvoiceStatusDraft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived members: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived members: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// Memberships, with their freezes and history, are removed by the cascade
+	// from members, but payments, credits and invoices have to be removed first.
+	queries := []string{
+		`DELETE FROM member_credits WHERE member_id = ANY($1)`,
+		`DELETE FROM payments WHERE membership_id IN (SELECT id FROM memberships WHERE member_id = ANY($1))`,
+		`DELETE FROM invoices WHERE member_id = ANY($1)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query, ids); err != nil {
+			return nil, fmt.Errorf("failed to purge members: %w", err)
+		}
+	}
+
+	query = `
+		DELETE FROM members
+		WHERE id = ANY($1)
+	`
+	if _, err := tx.Exec(ctx, query, ids); err != nil {
+		return nil, fmt.Errorf("failed to purge members: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to purge members: %w", err)
+	}
+	return ids, nil
+}
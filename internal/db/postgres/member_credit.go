@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetMemberCredits returns the credit ledger of the member, oldest first.
+func (s *MemberStore) GetMemberCredits(ctx context.Context, memberID uuid.UUID) ([]*model.MemberCredit, error) {
+	query := `
+		SELECT id, member_id, currency, amount, membership_id, invoice_id, reason, created_at
+		FROM member_credits
+		WHERE member_id = $1
+		ORDER BY created_at
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member credits: %w", err)
+	}
+	defer rows.Close()
+
+	var credits []*model.MemberCredit
+	for rows.Next() {
+		var credit model.MemberCredit
+		if err := rows.Scan(
+			&credit.ID,
+			&credit.MemberID,
+			&credit.Amount.Currency,
+			&credit.Amount,
+			&credit.MembershipID,
+			&credit.InvoiceID,
+			&credit.Reason,
+			&credit.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan member credit: %w", err)
+		}
+		credits = append(credits, &credit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over member credits: %w", err)
+	}
+	return credits, nil
+}
+
+func addMemberCredit(ctx context.Context, tx pgx.Tx, credit *model.MemberCredit) error {
+	query := `
+		INSERT INTO member_credits (member_id, currency, amount, membership_id, invoice_id, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	args := []any{
+		credit.MemberID,
+		credit.Amount.Currency,
+		credit.Amount,
+		credit.MembershipID,
+		credit.InvoiceID,
+		credit.Reason,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&credit.ID, &credit.CreatedAt); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		default:
+			return fmt.Errorf("failed to add member credit: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockMemberCredit locks the member until the end of the transaction, so that
+// credit cannot be drawn twice, and returns its credit balance in the currency.
+func lockMemberCredit(ctx context.Context, tx pgx.Tx, memberID uuid.UUID, currency string) (model.Money, error) {
+	query := `
+		SELECT id
+		FROM members
+		WHERE id = $1
+		FOR NO KEY UPDATE
+	`
+	if err := tx.QueryRow(ctx, query, memberID).Scan(&memberID); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return model.Money{}, fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		default:
+			return model.Money{}, fmt.Errorf("failed to lock member: %w", err)
+		}
+	}
+
+	query = `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM member_credits
+		WHERE member_id = $1 AND currency = $2
+	`
+	balance := model.Money{Currency: currency}
+	if err := tx.QueryRow(ctx, query, memberID, currency).Scan(&balance); err != nil {
+		return model.Money{}, fmt.Errorf("failed to get member credit balance: %w", err)
+	}
+	return balance, nil
+}
+
+// lockMembersCredit locks the members, in ID order so that concurrent invoices
+// cannot deadlock, and returns their credit balances in the currency by member.
+func lockMembersCredit(ctx context.Context, tx pgx.Tx, memberIDs []uuid.UUID, currency string) (map[uuid.UUID]model.Money, error) {
+	sorted := slices.SortedFunc(slices.Values(memberIDs), func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	balances := make(map[uuid.UUID]model.Money, len(sorted))
+	for _, memberID := range sorted {
+		balance, err := lockMemberCredit(ctx, tx, memberID, currency)
+		if err != nil {
+			return nil, err
+		}
+		balances[memberID] = balance
+	}
+	return balances, nil
+}
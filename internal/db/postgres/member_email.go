@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// RequestEmailChange holds the new email of the member until it is confirmed,
+// replacing any earlier change awaiting confirmation.
+func (s *MemberStore) RequestEmailChange(ctx context.Context, change *model.EmailChange) error {
+	query := `
+		INSERT INTO member_email_changes (member_id, email, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (member_id) DO UPDATE
+		SET email = EXCLUDED.email, requested_at = now(), expires_at = EXCLUDED.expires_at
+		RETURNING requested_at
+	`
+	if err := s.conn.QueryRow(ctx, query, change.MemberID, change.Email, change.ExpiresAt).Scan(&change.RequestedAt); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		default:
+			return fmt.Errorf("failed to request email change: %w", err)
+		}
+	}
+	return nil
+}
+
+// ConfirmEmailChange replaces the member's email with the new one if the change
+// is still the one awaiting confirmation and has not expired, and returns the member.
+func (s *MemberStore) ConfirmEmailChange(ctx context.Context, change *model.EmailChange) (*model.Member, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		DELETE FROM member_email_changes
+		WHERE member_id = $1 AND email = $2 AND expires_at > now()
+	`
+	result, err := tx.Exec(ctx, query, change.MemberID, change.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm email change: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrEmailChangeNotFound
+	}
+
+	query = `
+		UPDATE members
+		SET email = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, name, email, phone, COALESCE(address, ''), join_date, status, created_at, deleted_at, photo_updated_at, custom_fields
+	`
+	var member model.Member
+	if err := tx.QueryRow(ctx, query, change.MemberID, change.Email).Scan(
+		&member.ID,
+		&member.Name,
+		&member.Email,
+		&member.PhoneNumber,
+		&member.Address,
+		&member.JoinDate,
+		&member.Status,
+		&member.CreatedAt,
+		&member.DeletedAt,
+		&member.PhotoUpdatedAt,
+		&member.CustomFields,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		case IsPgError(err, PgUniqueViolation):
+			return nil, fmt.Errorf("%w: %w", ErrMemberAlreadyExists, err)
+		default:
+			return nil, fmt.Errorf("failed to confirm email change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to confirm email change: %w", err)
+	}
+	return &member, nil
+}
@@ -0,0 +1,344 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// FindDuplicateMembers returns up to limit pairs of current members that share a
+// phone number or email local part, or whose names have at least minSimilarity,
+// the pairs matching on most reasons first. Candidate pairs are found through the
+// indexes on the phone and email keys and the trigram index on names.
+func (s *MemberStore) FindDuplicateMembers(ctx context.Context, minSimilarity float64, limit int) ([]*model.DuplicateMatch, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The % operator matches names with at least this similarity.
+	query := `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`
+	if _, err := tx.Exec(ctx, query, strconv.FormatFloat(minSimilarity, 'f', -1, 64)); err != nil {
+		return nil, fmt.Errorf("failed to set similarity threshold: %w", err)
+	}
+
+	query = `
+		WITH pairs AS (
+			SELECT a.id AS a_id, b.id AS b_id
+			FROM members a
+			JOIN members b ON b.phone_key = a.phone_key AND a.id < b.id
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL AND a.phone_key <> ''
+			UNION
+			SELECT a.id, b.id
+			FROM members a
+			JOIN members b ON b.email_key = a.email_key AND a.id < b.id
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL AND a.email_key <> ''
+			UNION
+			SELECT a.id, b.id
+			FROM members a
+			JOIN members b ON b.name % a.name AND a.id < b.id
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+		)
+		SELECT a.id, a.name, a.email, a.phone, COALESCE(a.address, ''), a.join_date, a.status, a.created_at, a.deleted_at, a.photo_updated_at, a.custom_fields,
+			b.id, b.name, b.email, b.phone, COALESCE(b.address, ''), b.join_date, b.status, b.created_at, b.deleted_at, b.photo_updated_at, b.custom_fields,
+			a.phone_key <> '' AND a.phone_key = b.phone_key AS same_phone,
+			a.email_key <> '' AND a.email_key = b.email_key AS same_email,
+			similarity(a.name, b.name) AS name_similarity
+		FROM pairs p
+		JOIN members a ON a.id = p.a_id
+		JOIN members b ON b.id = p.b_id
+		ORDER BY (a.phone_key <> '' AND a.phone_key = b.phone_key)::INT + (a.email_key <> '' AND a.email_key = b.email_key)::INT
+			+ (similarity(a.name, b.name) >= $1)::INT DESC, name_similarity DESC, a.name, a.id, b.id
+		LIMIT $2
+	`
+	rows, err := tx.Query(ctx, query, minSimilarity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate members: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []*model.DuplicateMatch
+	for rows.Next() {
+		var a, b model.Member
+		var samePhone, sameEmail bool
+		match := &model.DuplicateMatch{Member: &a, Duplicate: &b}
+		if err := rows.Scan(
+			&a.ID,
+			&a.Name,
+			&a.Email,
+			&a.PhoneNumber,
+			&a.Address,
+			&a.JoinDate,
+			&a.Status,
+			&a.CreatedAt,
+			&a.DeletedAt,
+			&a.PhotoUpdatedAt,
+			&a.CustomFields,
+			&b.ID,
+			&b.Name,
+			&b.Email,
+			&b.PhoneNumber,
+			&b.Address,
+			&b.JoinDate,
+			&b.Status,
+			&b.CreatedAt,
+			&b.DeletedAt,
+			&b.PhotoUpdatedAt,
+			&b.CustomFields,
+			&samePhone,
+			&sameEmail,
+			&match.NameSimilarity,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate members: %w", err)
+		}
+
+		if samePhone {
+			match.Reasons = append(match.Reasons, model.DuplicateReasonPhone)
+		}
+		if sameEmail {
+			match.Reasons = append(match.Reasons, model.DuplicateReasonEmail)
+		}
+		if match.NameSimilarity >= minSimilarity {
+			match.Reasons = append(match.Reasons, model.DuplicateReasonName)
+		}
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over duplicate members: %w", err)
+	}
+	return matches, nil
+}
+
+// MergeMembers moves the memberships, and with them the payments, the draft
+// invoices and the credit of the duplicate to the survivor. It moves the
+// duplicate's household membership unless the survivor already has one, in which
+// case the duplicate leaves its household. It gives the survivor the duplicate's
+// tags, fills in the survivor's missing custom field values, archives the
+// duplicate and records the merge. Issued invoices stay with the duplicate, as do
+// its emergency contacts and medical notes, which are encrypted for it.
+func (s *MemberStore) MergeMembers(ctx context.Context, merge *model.MemberMerge) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock both members, in a fixed order, so concurrent merges cannot interleave.
+	query := `
+		SELECT id
+		FROM members
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query, []uuid.UUID{merge.SurvivorID, merge.DuplicateID})
+	if err != nil {
+		return fmt.Errorf("failed to lock members: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return fmt.Errorf("failed to lock members: %w", err)
+	}
+	if len(ids) != 2 {
+		return ErrMemberNotFound
+	}
+
+	query = `
+		SELECT name, email, phone
+		FROM members
+		WHERE id = $1
+	`
+	if err := tx.QueryRow(ctx, query, merge.DuplicateID).Scan(
+		&merge.DuplicateName,
+		&merge.DuplicateEmail,
+		&merge.DuplicatePhone,
+	); err != nil {
+		return fmt.Errorf("failed to get duplicate member: %w", err)
+	}
+
+	query = `
+		UPDATE memberships
+		SET member_id = $1
+		WHERE member_id = $2
+		RETURNING id
+	`
+	rows, err = tx.Query(ctx, query, merge.SurvivorID, merge.DuplicateID)
+	if err != nil {
+		return fmt.Errorf("failed to move memberships: %w", err)
+	}
+	if merge.MembershipIDs, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID]); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrMergeConflict, err)
+		default:
+			return fmt.Errorf("failed to move memberships: %w", err)
+		}
+	}
+
+	query = `
+		UPDATE invoices
+		SET member_id = $1
+		WHERE member_id = $2 AND status = $3
+		RETURNING id
+	`
+	rows, err = tx.Query(ctx, query, merge.SurvivorID, merge.DuplicateID, model.InvoiceStatusDraft)
+	if err != nil {
+		return fmt.Errorf("failed to move invoices: %w", err)
+	}
+	if merge.InvoiceIDs, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID]); err != nil {
+		return fmt.Errorf("failed to move invoices: %w", err)
+	}
+
+	query = `
+		UPDATE member_credits
+		SET member_id = $1
+		WHERE member_id = $2
+	`
+	if _, err := tx.Exec(ctx, query, merge.SurvivorID, merge.DuplicateID); err != nil {
+		return fmt.Errorf("failed to move member credits: %w", err)
+	}
+
+	query = `
+		UPDATE household_members
+		SET member_id = $1
+		WHERE member_id = $2 AND NOT EXISTS (SELECT 1 FROM household_members WHERE member_id = $1)
+	`
+	if _, err := tx.Exec(ctx, query, merge.SurvivorID, merge.DuplicateID); err != nil {
+		return fmt.Errorf("failed to move household member: %w", err)
+	}
+
+	// Otherwise the archived duplicate leaves its household, handing over being
+	// the primary payer if the survivor is in the same household.
+	query = `
+		DELETE FROM household_members
+		WHERE member_id = $1
+		RETURNING household_id, is_primary
+	`
+	var householdID uuid.UUID
+	var primary bool
+	switch err := tx.QueryRow(ctx, query, merge.DuplicateID).Scan(&householdID, &primary); {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("failed to remove household member: %w", err)
+	case primary:
+		query = `
+			UPDATE household_members
+			SET is_primary = TRUE
+			WHERE member_id = $1 AND household_id = $2
+		`
+		if _, err := tx.Exec(ctx, query, merge.SurvivorID, householdID); err != nil {
+			return fmt.Errorf("failed to hand over primary payer: %w", err)
+		}
+	}
+
+	// The survivor takes the duplicate's custom field values it has none for.
+	query = `
+		UPDATE members s
+		SET custom_fields = d.custom_fields || s.custom_fields
+		FROM members d
+		WHERE s.id = $1 AND d.id = $2
+	`
+	if _, err := tx.Exec(ctx, query, merge.SurvivorID, merge.DuplicateID); err != nil {
+		return fmt.Errorf("failed to merge custom fields: %w", err)
+	}
+
+	query = `
+		INSERT INTO member_tags (member_id, tag)
+		SELECT $1, tag
+		FROM member_tags
+		WHERE member_id = $2
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, query, merge.SurvivorID, merge.DuplicateID); err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	query = `
+		DELETE FROM member_tags
+		WHERE member_id = $1
+	`
+	if _, err := tx.Exec(ctx, query, merge.DuplicateID); err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	query = `
+		UPDATE members
+		SET deleted_at = now()
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, query, merge.DuplicateID); err != nil {
+		return fmt.Errorf("failed to archive duplicate member: %w", err)
+	}
+
+	query = `
+		INSERT INTO member_merges (survivor_id, duplicate_id, duplicate_name, duplicate_email, duplicate_phone, membership_ids, invoice_ids, actor)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, merged_at
+	`
+	if merge.MembershipIDs == nil {
+		merge.MembershipIDs = []uuid.UUID{}
+	}
+	if merge.InvoiceIDs == nil {
+		merge.InvoiceIDs = []uuid.UUID{}
+	}
+	args := []any{
+		merge.SurvivorID,
+		merge.DuplicateID,
+		merge.DuplicateName,
+		merge.DuplicateEmail,
+		merge.DuplicatePhone,
+		merge.MembershipIDs,
+		merge.InvoiceIDs,
+		merge.Actor,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&merge.ID, &merge.MergedAt); err != nil {
+		return fmt.Errorf("failed to record member merge: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetMemberMerges returns the merges the member took part in, as survivor or duplicate, latest first.
+func (s *MemberStore) GetMemberMerges(ctx context.Context, memberID uuid.UUID) ([]*model.MemberMerge, error) {
+	query := `
+		SELECT id, survivor_id, duplicate_id, duplicate_name, duplicate_email, duplicate_phone, membership_ids, invoice_ids, actor, merged_at
+		FROM member_merges
+		WHERE survivor_id = $1 OR duplicate_id = $1
+		ORDER BY merged_at DESC
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member merges: %w", err)
+	}
+	defer rows.Close()
+
+	var merges []*model.MemberMerge
+	for rows.Next() {
+		var merge model.MemberMerge
+		if err := rows.Scan(
+			&merge.ID,
+			&merge.SurvivorID,
+			&merge.DuplicateID,
+			&merge.DuplicateName,
+			&merge.DuplicateEmail,
+			&merge.DuplicatePhone,
+			&merge.MembershipIDs,
+			&merge.InvoiceIDs,
+			&merge.Actor,
+			&merge.MergedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan member merge: %w", err)
+		}
+		merges = append(merges, &merge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over member merges: %w", err)
+	}
+	return merges, nil
+}
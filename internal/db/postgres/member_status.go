@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetMemberStatusHistory returns the status changes of the member, oldest first.
+func (s *MemberStore) GetMemberStatusHistory(ctx context.Context, memberID uuid.UUID) ([]*model.MemberStatusChange, error) {
+	query := `
+		SELECT id, member_id, from_status, to_status, reason, note, actor, changed_at
+		FROM member_status_history
+		WHERE member_id = $1
+		ORDER BY changed_at
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member status history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*model.MemberStatusChange
+	for rows.Next() {
+		var change model.MemberStatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.MemberID,
+			&change.From,
+			&change.To,
+			&change.Reason,
+			&change.Note,
+			&change.Actor,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan member status change: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over member status history: %w", err)
+	}
+	return changes, nil
+}
+
+// lockMemberStatus locks the member until the end of the transaction and checks
+// it still has the status from.
+func lockMemberStatus(ctx context.Context, tx pgx.Tx, memberID uuid.UUID, from model.MemberStatus) error {
+	query := `
+		SELECT status
+		FROM members
+		WHERE id = $1
+		FOR UPDATE
+	`
+	var status model.MemberStatus
+	if err := tx.QueryRow(ctx, query, memberID).Scan(&status); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		default:
+			return fmt.Errorf("failed to lock member: %w", err)
+		}
+	}
+	if status != from {
+		return ErrMemberStatusChanged
+	}
+	return nil
+}
+
+func addMemberStatusChange(ctx context.Context, tx pgx.Tx, change *model.MemberStatusChange) error {
+	query := `
+		INSERT INTO member_status_history (member_id, from_status, to_status, reason, note, actor)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, changed_at
+	`
+	args := []any{
+		change.MemberID,
+		change.From,
+		change.To,
+		change.Reason,
+		change.Note,
+		change.Actor,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&change.ID, &change.ChangedAt); err != nil {
+		return fmt.Errorf("failed to add member status change: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetMemberTags returns the tags of the member in alphabetical order.
+func (s *MemberStore) GetMemberTags(ctx context.Context, memberID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT tag
+		FROM member_tags
+		WHERE member_id = $1
+		ORDER BY tag
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member tags: %w", err)
+	}
+	tags, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member tags: %w", err)
+	}
+	return tags, nil
+}
+
+// AddMemberTags tags the member. Tags the member already has are left as they are.
+func (s *MemberStore) AddMemberTags(ctx context.Context, memberID uuid.UUID, tags []string) error {
+	query := `
+		INSERT INTO member_tags (member_id, tag)
+		SELECT $1, unnest($2::TEXT[])
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := s.conn.Exec(ctx, query, memberID, tags); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		default:
+			return fmt.Errorf("failed to add member tags: %w", err)
+		}
+	}
+	return nil
+}
+
+// RemoveMemberTags removes the tags from the member. Tags the member does not have are ignored.
+func (s *MemberStore) RemoveMemberTags(ctx context.Context, memberID uuid.UUID, tags []string) error {
+	query := `
+		DELETE FROM member_tags
+		WHERE member_id = $1 AND tag = ANY($2)
+	`
+	if _, err := s.conn.Exec(ctx, query, memberID, tags); err != nil {
+		return fmt.Errorf("failed to remove member tags: %w", err)
+	}
+	return nil
+}
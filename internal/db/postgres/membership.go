@@ -2,9 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,26 +22,62 @@ func NewMembershipStore(conn *pgxpool.Pool) *MembershipStore {
 	}
 }
 
-func (s *MembershipStore) AddMembership(ctx context.Context, membership *model.Membership) error {
-	query := `INSERT INTO memberships (member_id, sport_id, type, start_date, due_date, status, fee) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+// AddMembership adds the membership and records its initial status in the status history.
+func (s *MembershipStore) AddMembership(ctx context.Context, membership *model.Membership, actor string) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := addMembership(ctx, tx, membership); err != nil {
+		return err
+	}
+
+	if err := addMembershipTransition(ctx, tx, &model.MembershipTransition{
+		MembershipID: membership.ID,
+		To:           membership.Status,
+		Actor:        actor,
+		Reason:       "created",
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func addMembership(ctx context.Context, tx pgx.Tx, membership *model.Membership) error {
+	query := `
+		INSERT INTO memberships (member_id, sport_id, plan_id, type, start_date, due_date, status, currency, fees, auto_renew, batch_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, member_id, sport_id, plan_id, type, start_date, due_date, status, currency, fees, auto_renew, batch_id
+	`
 	args := []any{
 		membership.MemberID,
 		membership.SportID,
+		membership.PlanID,
 		membership.Type,
 		membership.StartDate,
 		membership.DueDate,
 		membership.Status,
+		membership.Fee.Currency,
 		membership.Fee,
+		membership.AutoRenew,
+		membership.BatchID,
 	}
-	err := s.conn.QueryRow(ctx, query, args...).Scan(
+	err := tx.QueryRow(ctx, query, args...).Scan(
 		&membership.ID,
 		&membership.MemberID,
 		&membership.SportID,
+		&membership.PlanID,
 		&membership.Type,
 		&membership.StartDate,
 		&membership.DueDate,
 		&membership.Status,
+		&membership.Fee.Currency,
 		&membership.Fee,
+		&membership.AutoRenew,
+		&membership.BatchID,
 	)
 	if err != nil {
 		switch {
@@ -45,9 +85,415 @@ func (s *MembershipStore) AddMembership(ctx context.Context, membership *model.M
 			return fmt.Errorf("%w: %w", ErrMembershipAlreadyExists, err)
 		case IsPgError(err, PgNotNullViolation):
 			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		case IsPgConstraintError(err, PgForeignKeyViolation, membershipBatchConstraint):
+			return fmt.Errorf("%w: %w", ErrBatchNotFound, err)
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMembershipReferenceNotFound, err)
 		default:
 			return fmt.Errorf("failed to add membership: %w", err)
 		}
 	}
+
+	if membership.BatchID != nil {
+		return enrolInBatch(ctx, tx, membership)
+	}
 	return nil
 }
+
+func (s *MembershipStore) GetMembershipByID(ctx context.Context, id uuid.UUID) (*model.Membership, error) {
+	query := `
+		SELECT id, member_id, sport_id, plan_id, type, start_date, due_date, status, currency, fees, auto_renew, batch_id
+		FROM memberships
+		WHERE id = $1
+	`
+	var membership model.Membership
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&membership.ID,
+		&membership.MemberID,
+		&membership.SportID,
+		&membership.PlanID,
+		&membership.Type,
+		&membership.StartDate,
+		&membership.DueDate,
+		&membership.Status,
+		&membership.Fee.Currency,
+		&membership.Fee,
+		&membership.AutoRenew,
+		&membership.BatchID,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrMembershipNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get membership: %w", err)
+		}
+	}
+	return &membership, nil
+}
+
+func (s *MembershipStore) GetAllMemberships(ctx context.Context) ([]*model.Membership, error) {
+	query := `
+		SELECT id, member_id, sport_id, plan_id, type, start_date, due_date, status, currency, fees, auto_renew, batch_id
+		FROM memberships
+	`
+	rows, err := s.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []*model.Membership
+	for rows.Next() {
+		var membership model.Membership
+		if err := rows.Scan(
+			&membership.ID,
+			&membership.MemberID,
+			&membership.SportID,
+			&membership.PlanID,
+			&membership.Type,
+			&membership.StartDate,
+			&membership.DueDate,
+			&membership.Status,
+			&membership.Fee.Currency,
+			&membership.Fee,
+			&membership.AutoRenew,
+			&membership.BatchID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, &membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+func (s *MembershipStore) GetMembershipsByMemberID(ctx context.Context, memberID uuid.UUID) ([]*model.Membership, error) {
+	query := `
+		SELECT id, member_id, sport_id, plan_id, type, start_date, due_date, status, currency, fees, auto_renew, batch_id
+		FROM memberships
+		WHERE member_id = $1
+		ORDER BY start_date
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []*model.Membership
+	for rows.Next() {
+		var membership model.Membership
+		if err := rows.Scan(
+			&membership.ID,
+			&membership.MemberID,
+			&membership.SportID,
+			&membership.PlanID,
+			&membership.Type,
+			&membership.StartDate,
+			&membership.DueDate,
+			&membership.Status,
+			&membership.Fee.Currency,
+			&membership.Fee,
+			&membership.AutoRenew,
+			&membership.BatchID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, &membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// UpdateMembership updates the membership. Moving it to another batch fails with
+// ErrBatchFull if that batch has no places left.
+func (s *MembershipStore) UpdateMembership(ctx context.Context, membership *model.Membership) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT batch_id
+		FROM memberships
+		WHERE id = $1
+		FOR UPDATE
+	`
+	var batchID *uuid.UUID
+	if err := tx.QueryRow(ctx, query, membership.ID).Scan(&batchID); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrMembershipNotFound, err)
+		default:
+			return fmt.Errorf("failed to get membership batch: %w", err)
+		}
+	}
+	enrol := membership.BatchID != nil && (batchID == nil || *batchID != *membership.BatchID)
+
+	query = `
+		UPDATE memberships
+		SET type = $1, start_date = $2, due_date = $3, currency = $4, fees = $5, auto_renew = $6, batch_id = $8
+		WHERE id = $7
+		RETURNING id, member_id, sport_id, plan_id, type, start_date, due_date, status, currency, fees, auto_renew, batch_id
+	`
+	args := []any{
+		membership.Type,
+		membership.StartDate,
+		membership.DueDate,
+		membership.Fee.Currency,
+		membership.Fee,
+		membership.AutoRenew,
+		membership.ID,
+		membership.BatchID,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(
+		&membership.ID,
+		&membership.MemberID,
+		&membership.SportID,
+		&membership.PlanID,
+		&membership.Type,
+		&membership.StartDate,
+		&membership.DueDate,
+		&membership.Status,
+		&membership.Fee.Currency,
+		&membership.Fee,
+		&membership.AutoRenew,
+		&membership.BatchID,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrMembershipNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrMembershipAlreadyExists, err)
+		case IsPgConstraintError(err, PgForeignKeyViolation, membershipBatchConstraint):
+			return fmt.Errorf("%w: %w", ErrBatchNotFound, err)
+		default:
+			return fmt.Errorf("failed to update membership: %w", err)
+		}
+	}
+
+	if enrol {
+		if err := enrolInBatch(ctx, tx, membership); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update membership: %w", err)
+	}
+	return nil
+}
+
+// TransitionOverdueMemberships moves every membership in status from whose due date
+// is before dueBefore to status to, and records the transitions. Active memberships
+// that will be renewed, and memberships of archived members, are left alone. It
+// returns the number of moved memberships.
+func (s *MembershipStore) TransitionOverdueMemberships(ctx context.Context, from, to model.MembershipStatus, dueBefore time.Time, actor, reason string) (int64, error) {
+	query := `
+		WITH changed AS (
+			UPDATE memberships
+			SET status = $1
+			WHERE status = $2 AND due_date < $3
+				AND NOT (status = $4 AND auto_renew AND due_date > start_date)
+				AND member_id IN (SELECT id FROM members WHERE deleted_at IS NULL)
+			RETURNING id
+		)
+		INSERT INTO membership_status_history (membership_id, from_status, to_status, actor, reason)
+		SELECT id, $2, $1, $5, $6
+		FROM changed
+	`
+	rows, err := s.conn.Exec(ctx, query, to, from, dueBefore, model.MembershipActive, actor, reason)
+	if err != nil {
+		return 0, fmt.Errorf("failed to transition overdue memberships: %w", err)
+	}
+	return rows.RowsAffected(), nil
+}
+
+// GetRenewableMemberships returns the active, auto-renewing memberships whose due
+// date is before now. Memberships of archived members are not renewed.
+func (s *MembershipStore) GetRenewableMemberships(ctx context.Context, now time.Time) ([]*model.Membership, error) {
+	query := `
+		SELECT ms.id, ms.member_id, ms.sport_id, ms.plan_id, ms.type, ms.start_date, ms.due_date, ms.status, ms.currency, ms.fees, ms.auto_renew, ms.batch_id
+		FROM memberships ms
+		JOIN members m ON m.id = ms.member_id
+		WHERE ms.status = $1 AND ms.auto_renew AND ms.due_date < $2 AND ms.due_date > ms.start_date
+			AND m.deleted_at IS NULL
+	`
+	rows, err := s.conn.Query(ctx, query, model.MembershipActive, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renewable memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []*model.Membership
+	for rows.Next() {
+		var membership model.Membership
+		if err := rows.Scan(
+			&membership.ID,
+			&membership.MemberID,
+			&membership.SportID,
+			&membership.PlanID,
+			&membership.Type,
+			&membership.StartDate,
+			&membership.DueDate,
+			&membership.Status,
+			&membership.Fee.Currency,
+			&membership.Fee,
+			&membership.AutoRenew,
+			&membership.BatchID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, &membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over memberships: %w", err)
+	}
+	return memberships, nil
+}
+
+// RenewMembership expires the current membership and adds next in a single
+// transaction, recording both in the status history.
+func (s *MembershipStore) RenewMembership(ctx context.Context, current, next *model.Membership, actor string) error {
+	transition, err := current.Transition(model.MembershipExpired, actor, "renewed")
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updateMembershipStatus(ctx, tx, transition); err != nil {
+		return err
+	}
+
+	if err := addMembership(ctx, tx, next); err != nil {
+		return err
+	}
+
+	if err := addMembershipTransition(ctx, tx, &model.MembershipTransition{
+		MembershipID: next.ID,
+		To:           next.Status,
+		Actor:        actor,
+		Reason:       fmt.Sprintf("renewal of %s", current.ID),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to renew membership: %w", err)
+	}
+	current.Status = transition.To
+	return nil
+}
+
+// ChangeMembership closes the current membership on the start date of next by
+// applying the transition, and adds next in the same transaction. The credit, if
+// not nil, is granted to the member.
+func (s *MembershipStore) ChangeMembership(ctx context.Context, current, next *model.Membership, transition *model.MembershipTransition, credit *model.MemberCredit) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE memberships
+		SET due_date = $1
+		WHERE id = $2
+	`
+	if _, err := tx.Exec(ctx, query, next.StartDate, current.ID); err != nil {
+		return fmt.Errorf("failed to close membership: %w", err)
+	}
+
+	if err := updateMembershipStatus(ctx, tx, transition); err != nil {
+		return err
+	}
+
+	if err := addMembership(ctx, tx, next); err != nil {
+		return err
+	}
+
+	if err := addMembershipTransition(ctx, tx, &model.MembershipTransition{
+		MembershipID: next.ID,
+		To:           next.Status,
+		Actor:        transition.Actor,
+		Reason:       fmt.Sprintf("changed from %s", current.ID),
+	}); err != nil {
+		return err
+	}
+
+	if credit != nil {
+		if err := addMemberCredit(ctx, tx, credit); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to change membership: %w", err)
+	}
+	current.DueDate = next.StartDate
+	current.Status = transition.To
+	return nil
+}
+
+// GetOutstandingDues returns every membership with an unpaid balance, together with
+// its member and sport. Cancelled memberships and archived members are left out
+// and only completed payments count towards the balance. If sportID is not nil, only memberships of
+// that sport are returned.
+func (s *MembershipStore) GetOutstandingDues(ctx context.Context, sportID *uuid.UUID) ([]*model.Due, error) {
+	query := `
+		SELECT m.id, m.name, m.email, m.phone, ms.id, ms.sport_id, s.name, ms.due_date,
+			ms.currency, ms.fees,
+			ms.currency, COALESCE(SUM(p.amount) FILTER (WHERE p.status = $1 AND p.currency = ms.currency), 0)
+		FROM memberships ms
+		JOIN members m ON m.id = ms.member_id
+		JOIN sports s ON s.id = ms.sport_id
+		LEFT JOIN payments p ON p.membership_id = ms.id
+		WHERE ($2::uuid IS NULL OR ms.sport_id = $2) AND ms.status <> $3 AND m.deleted_at IS NULL
+		GROUP BY m.id, ms.id, s.id
+		HAVING ms.fees > COALESCE(SUM(p.amount) FILTER (WHERE p.status = $1 AND p.currency = ms.currency), 0)
+		ORDER BY ms.due_date, m.name
+	`
+	rows, err := s.conn.Query(ctx, query, model.PaymentStatusCompleted, sportID, model.MembershipCancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outstanding dues: %w", err)
+	}
+	defer rows.Close()
+
+	var dues []*model.Due
+	for rows.Next() {
+		var due model.Due
+		if err := rows.Scan(
+			&due.MemberID,
+			&due.MemberName,
+			&due.Email,
+			&due.PhoneNumber,
+			&due.MembershipID,
+			&due.SportID,
+			&due.SportName,
+			&due.DueDate,
+			&due.Fee.Currency,
+			&due.Fee,
+			&due.Paid.Currency,
+			&due.Paid,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan due: %w", err)
+		}
+		dues = append(dues, &due)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over dues: %w", err)
+	}
+	return dues, nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// TransitionMembership applies the status transition to the membership and records it in the status history.
+// Leaving the frozen status ends the freeze in progress without crediting any days.
+func (s *MembershipStore) TransitionMembership(ctx context.Context, membership *model.Membership, transition *model.MembershipTransition) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updateMembershipStatus(ctx, tx, transition); err != nil {
+		return err
+	}
+
+	if transition.From != nil && *transition.From == model.MembershipFrozen {
+		query := `
+			UPDATE membership_freezes
+			SET end_date = now()
+			WHERE membership_id = $1 AND end_date IS NULL
+		`
+		if _, err := tx.Exec(ctx, query, membership.ID); err != nil {
+			return fmt.Errorf("failed to end freeze: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to transition membership: %w", err)
+	}
+	membership.Status = transition.To
+	return nil
+}
+
+func (s *MembershipStore) GetMembershipHistory(ctx context.Context, membershipID uuid.UUID) ([]*model.MembershipTransition, error) {
+	query := `
+		SELECT id, membership_id, from_status, to_status, actor, reason, changed_at
+		FROM membership_status_history
+		WHERE membership_id = $1
+		ORDER BY changed_at
+	`
+	rows, err := s.conn.Query(ctx, query, membershipID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership history: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []*model.MembershipTransition
+	for rows.Next() {
+		var transition model.MembershipTransition
+		if err := rows.Scan(
+			&transition.ID,
+			&transition.MembershipID,
+			&transition.From,
+			&transition.To,
+			&transition.Actor,
+			&transition.Reason,
+			&transition.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan membership transition: %w", err)
+		}
+		transitions = append(transitions, &transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over membership history: %w", err)
+	}
+	return transitions, nil
+}
+
+// updateMembershipStatus moves the membership from transition.From to transition.To
+// and records the transition. It fails with ErrMembershipStatusChanged if the
+// membership no longer has the status the transition starts from.
+func updateMembershipStatus(ctx context.Context, tx pgx.Tx, transition *model.MembershipTransition) error {
+	query := `
+		UPDATE memberships
+		SET status = $1
+		WHERE id = $2 AND status = $3
+	`
+	rows, err := tx.Exec(ctx, query, transition.To, transition.MembershipID, transition.From)
+	if err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrMembershipAlreadyExists, err)
+		default:
+			return fmt.Errorf("failed to update membership status: %w", err)
+		}
+	}
+	if rows.RowsAffected() == 0 {
+		return ErrMembershipStatusChanged
+	}
+
+	return addMembershipTransition(ctx, tx, transition)
+}
+
+func addMembershipTransition(ctx context.Context, tx pgx.Tx, transition *model.MembershipTransition) error {
+	query := `
+		INSERT INTO membership_status_history (membership_id, from_status, to_status, actor, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, changed_at
+	`
+	args := []any{
+		transition.MembershipID,
+		transition.From,
+		transition.To,
+		transition.Actor,
+		transition.Reason,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&transition.ID, &transition.ChangedAt); err != nil {
+		return fmt.Errorf("failed to add membership transition: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PaymentStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewPaymentStore(conn *pgxpool.Pool) *PaymentStore {
+	return &PaymentStore{
+		conn: conn,
+	}
+}
+
+// AddPayment records a payment of the membership. The membership is locked while
+// the payment is added, so that concurrent payments cannot together exceed the
+// outstanding balance; a completed payment beyond it is rejected.
+func (s *PaymentStore) AddPayment(ctx context.Context, payment *model.Payment) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	balance, err := lockMembershipBalance(ctx, tx, payment.MembershipID)
+	if err != nil {
+		return err
+	}
+	if !payment.Amount.SameCurrency(balance) {
+		return fmt.Errorf("%w: payment in %s, fee in %s", model.ErrCurrencyMismatch, payment.Amount.Currency, balance.Currency)
+	}
+	if payment.Status == model.PaymentStatusCompleted && payment.Amount.Cmp(balance) > 0 {
+		return fmt.Errorf("%w: %s paid, %s outstanding", ErrPaymentExceedsBalance, payment.Amount, balance)
+	}
+
+	query := `
+		INSERT INTO payments (membership_id, currency, amount, payment_date, status, payment_link)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING id, membership_id, currency, amount, payment_date, status, COALESCE(payment_link, '')
+	`
+	args := []any{
+		payment.MembershipID,
+		payment.Amount.Currency,
+		payment.Amount,
+		payment.PaymentDate,
+		payment.Status,
+		payment.PaymentLink,
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(
+		&payment.ID,
+		&payment.MembershipID,
+		&payment.Amount.Currency,
+		&payment.Amount,
+		&payment.PaymentDate,
+		&payment.Status,
+		&payment.PaymentLink,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMembershipNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add payment: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// lockMembershipBalance locks the membership until the end of the transaction
+// and returns its outstanding balance, as model.Balance computes it.
+func lockMembershipBalance(ctx context.Context, tx pgx.Tx, membershipID uuid.UUID) (model.Money, error) {
+	query := `
+		SELECT currency, fees
+		FROM memberships
+		WHERE id = $1
+		FOR UPDATE
+	`
+	var fee model.Money
+	if err := tx.QueryRow(ctx, query, membershipID).Scan(&fee.Currency, &fee); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return model.Money{}, fmt.Errorf("%w: %w", ErrMembershipNotFound, err)
+		default:
+			return model.Money{}, fmt.Errorf("failed to lock membership: %w", err)
+		}
+	}
+
+	query = `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM payments
+		WHERE membership_id = $1 AND status = $2 AND currency = $3
+	`
+	paid := model.Money{Currency: fee.Currency}
+	if err := tx.QueryRow(ctx, query, membershipID, model.PaymentStatusCompleted, fee.Currency).Scan(&paid); err != nil {
+		return model.Money{}, fmt.Errorf("failed to get membership payments: %w", err)
+	}
+	return fee.Sub(paid), nil
+}
+
+func (s *PaymentStore) GetPaymentByID(ctx context.Context, id uuid.UUID) (*model.Payment, error) {
+	query := `
+		SELECT id, membership_id, currency, amount, payment_date, status, COALESCE(payment_link, '')
+		FROM payments
+		WHERE id = $1
+	`
+	var payment model.Payment
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&payment.ID,
+		&payment.MembershipID,
+		&payment.Amount.Currency,
+		&payment.Amount,
+		&payment.PaymentDate,
+		&payment.Status,
+		&payment.PaymentLink,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrPaymentNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get payment: %w", err)
+		}
+	}
+	return &payment, nil
+}
+
+func (s *PaymentStore) GetPaymentsByMembershipID(ctx context.Context, membershipID uuid.UUID) ([]*model.Payment, error) {
+	query := `
+		SELECT id, membership_id, currency, amount, payment_date, status, COALESCE(payment_link, '')
+		FROM payments
+		WHERE membership_id = $1
+		ORDER BY payment_date
+	`
+	rows, err := s.conn.Query(ctx, query, membershipID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []*model.Payment
+	for rows.Next() {
+		var payment model.Payment
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.MembershipID,
+			&payment.Amount.Currency,
+			&payment.Amount,
+			&payment.PaymentDate,
+			&payment.Status,
+			&payment.PaymentLink,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, &payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over payments: %w", err)
+	}
+	return payments, nil
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PlanStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewPlanStore(conn *pgxpool.Pool) *PlanStore {
+	return &PlanStore{
+		conn: conn,
+	}
+}
+
+func (s *PlanStore) AddPlan(ctx context.Context, plan *model.Plan) error {
+	query := `
+		INSERT INTO plans (sport_id, name, billing_period, currency, price, type, max_freeze_days, min_freeze_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, sport_id, name, billing_period, currency, price, type, max_freeze_days, min_freeze_days
+	`
+	args := []any{
+		plan.SportID,
+		plan.Name,
+		plan.BillingPeriod,
+		plan.Price.Currency,
+		plan.Price,
+		plan.Type,
+		plan.MaxFreezeDays,
+		plan.MinFreezeDays,
+	}
+	if err := s.conn.QueryRow(ctx, query, args...).Scan(
+		&plan.ID,
+		&plan.SportID,
+		&plan.Name,
+		&plan.BillingPeriod,
+		&plan.Price.Currency,
+		&plan.Price,
+		&plan.Type,
+		&plan.MaxFreezeDays,
+		&plan.MinFreezeDays,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrPlanAlreadyExists, err)
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrSportNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add plan: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *PlanStore) GetPlanByID(ctx context.Context, id uuid.UUID) (*model.Plan, error) {
+	query := `
+		SELECT id, sport_id, name, billing_period, currency, price, type, max_freeze_days, min_freeze_days
+		FROM plans
+		WHERE id = $1
+	`
+	var plan model.Plan
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&plan.ID,
+		&plan.SportID,
+		&plan.Name,
+		&plan.BillingPeriod,
+		&plan.Price.Currency,
+		&plan.Price,
+		&plan.Type,
+		&plan.MaxFreezeDays,
+		&plan.MinFreezeDays,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrPlanNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get plan: %w", err)
+		}
+	}
+	return &plan, nil
+}
+
+func (s *PlanStore) GetPlansBySportID(ctx context.Context, sportID uuid.UUID) ([]*model.Plan, error) {
+	query := `
+		SELECT id, sport_id, name, billing_period, currency, price, type, max_freeze_days, min_freeze_days
+		FROM plans
+		WHERE sport_id = $1
+		ORDER BY name
+	`
+	rows, err := s.conn.Query(ctx, query, sportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans: %w", err)
+	}
+	defer rows.Close()
+
+	var plans []*model.Plan
+	for rows.Next() {
+		var plan model.Plan
+		if err := rows.Scan(
+			&plan.ID,
+			&plan.SportID,
+			&plan.Name,
+			&plan.BillingPeriod,
+			&plan.Price.Currency,
+			&plan.Price,
+			&plan.Type,
+			&plan.MaxFreezeDays,
+			&plan.MinFreezeDays,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan plan: %w", err)
+		}
+		plans = append(plans, &plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over plans: %w", err)
+	}
+	return plans, nil
+}
+
+func (s *PlanStore) UpdatePlan(ctx context.Context, plan *model.Plan) error {
+	query := `
+		UPDATE plans
+		SET name = $1, billing_period = $2, currency = $3, price = $4, type = $5, max_freeze_days = $6, min_freeze_days = $7
+		WHERE id = $8
+		RETURNING id, sport_id, name, billing_period, currency, price, type, max_freeze_days, min_freeze_days
+	`
+	args := []any{
+		plan.Name,
+		plan.BillingPeriod,
+		plan.Price.Currency,
+		plan.Price,
+		plan.Type,
+		plan.MaxFreezeDays,
+		plan.MinFreezeDays,
+		plan.ID,
+	}
+	if err := s.conn.QueryRow(ctx, query, args...).Scan(
+		&plan.ID,
+		&plan.SportID,
+		&plan.Name,
+		&plan.BillingPeriod,
+		&plan.Price.Currency,
+		&plan.Price,
+		&plan.Type,
+		&plan.MaxFreezeDays,
+		&plan.MinFreezeDays,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrPlanNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrPlanAlreadyExists, err)
+		default:
+			return fmt.Errorf("failed to update plan: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *PlanStore) DeletePlan(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM plans
+		WHERE id = $1
+	`
+	rows, err := s.conn.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete plan: %w", err)
+	}
+	if rows.RowsAffected() == 0 {
+		return ErrPlanNotFound
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SegmentStore struct {
+	conn *pgxpool.Pool
+}
+
+func NewSegmentStore(conn *pgxpool.Pool) *SegmentStore {
+	return &SegmentStore{
+		conn: conn,
+	}
+}
+
+func (s *SegmentStore) AddSegment(ctx context.Context, segment *model.Segment) error {
+	query := `
+		INSERT INTO segments (name, filter)
+		VALUES ($1, $2)
+		RETURNING id, name, filter, created_at
+	`
+	if err := s.conn.QueryRow(ctx, query, segment.Name, segment.Filter).Scan(
+		&segment.ID,
+		&segment.Name,
+		&segment.Filter,
+		&segment.CreatedAt,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrSegmentAlreadyExists, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add segment: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SegmentStore) GetSegmentByID(ctx context.Context, id uuid.UUID) (*model.Segment, error) {
+	query := `
+		SELECT id, name, filter, created_at
+		FROM segments
+		WHERE id = $1
+	`
+	var segment model.Segment
+	if err := s.conn.QueryRow(ctx, query, id).Scan(
+		&segment.ID,
+		&segment.Name,
+		&segment.Filter,
+		&segment.CreatedAt,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, fmt.Errorf("%w: %w", ErrSegmentNotFound, err)
+		default:
+			return nil, fmt.Errorf("failed to get segment: %w", err)
+		}
+	}
+	return &segment, nil
+}
+
+// GetSegments returns every segment, by name.
+func (s *SegmentStore) GetSegments(ctx context.Context) ([]*model.Segment, error) {
+	query := `
+		SELECT id, name, filter, created_at
+		FROM segments
+		ORDER BY name
+	`
+	rows, err := s.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segments: %w", err)
+	}
+	defer rows.Close()
+
+	var segments []*model.Segment
+	for rows.Next() {
+		var segment model.Segment
+		if err := rows.Scan(
+			&segment.ID,
+			&segment.Name,
+			&segment.Filter,
+			&segment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan segment: %w", err)
+		}
+		segments = append(segments, &segment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over segments: %w", err)
+	}
+	return segments, nil
+}
+
+func (s *SegmentStore) UpdateSegment(ctx context.Context, segment *model.Segment) error {
+	query := `
+		UPDATE segments
+		SET name = $1, filter = $2
+		WHERE id = $3
+		RETURNING id, name, filter, created_at
+	`
+	if err := s.conn.QueryRow(ctx, query, segment.Name, segment.Filter, segment.ID).Scan(
+		&segment.ID,
+		&segment.Name,
+		&segment.Filter,
+		&segment.CreatedAt,
+	); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: %w", ErrSegmentNotFound, err)
+		case IsPgError(err, PgUniqueViolation):
+			return fmt.Errorf("%w: %w", ErrSegmentAlreadyExists, err)
+		default:
+			return fmt.Errorf("failed to update segment: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SegmentStore) DeleteSegment(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM segments
+		WHERE id = $1
+	`
+	result, err := s.conn.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete segment: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrSegmentNotFound
+	}
+	return nil
+}
+
+// segmentConds appends to conds and args the conditions on the members table
+// selecting the members matching the filter.
+func segmentConds(f *model.SegmentFilter, conds []string, args []any) ([]string, []any) {
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Status != nil {
+		conds = append(conds, "status = "+arg(*f.Status))
+	}
+	if len(f.Tags) > 0 {
+		conds = append(conds, tagsCond(arg(f.Tags)))
+	}
+	if len(f.CustomFields) > 0 {
+		conds = append(conds, fmt.Sprintf("custom_fields @> %s::JSONB", arg(f.CustomFields)))
+	}
+	if f.JoinedAfter != nil {
+		conds = append(conds, "join_date >= "+arg(*f.JoinedAfter))
+	}
+	if f.JoinedBefore != nil {
+		conds = append(conds, "join_date < "+arg(*f.JoinedBefore))
+	}
+
+	// The membership conditions all apply to the same membership.
+	var membership []string
+	if f.SportID != nil {
+		membership = append(membership, "ms.sport_id = "+arg(*f.SportID))
+	}
+	if f.MembershipStatus != nil {
+		membership = append(membership, "ms.status = "+arg(*f.MembershipStatus))
+	}
+	var owing string
+	if f.Owing != nil {
+		owing = fmt.Sprintf(`ms.status <> %s AND ms.fees > (
+			SELECT COALESCE(SUM(p.amount), 0)
+			FROM payments p
+			WHERE p.membership_id = ms.id AND p.status = %s AND p.currency = ms.currency
+		)`, arg(model.MembershipCancelled), arg(model.PaymentStatusCompleted))
+		if *f.Owing {
+			membership = append(membership, owing)
+		} else {
+			conds = append(conds, "NOT EXISTS (SELECT 1 FROM memberships ms WHERE ms.member_id = members.id AND "+owing+")")
+		}
+	}
+	if len(membership) > 0 {
+		conds = append(conds, "EXISTS (SELECT 1 FROM memberships ms WHERE ms.member_id = members.id AND "+strings.Join(membership, " AND ")+")")
+	}
+
+	return conds, args
+}
+
+// tagsCond returns the condition on the members table selecting the members
+// with every tag in the TEXT[] parameter param.
+func tagsCond(param string) string {
+	return fmt.Sprintf("ARRAY(SELECT tag FROM member_tags t WHERE t.member_id = members.id) @> %s::TEXT[]", param)
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/crypt"
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SensitiveStore keeps the emergency contacts and medical notes of members. Their
+// personal columns are encrypted with the cipher before they reach the database.
+type SensitiveStore struct {
+	conn   *pgxpool.Pool
+	cipher *crypt.Cipher
+}
+
+func NewSensitiveStore(conn *pgxpool.Pool, cipher *crypt.Cipher) *SensitiveStore {
+	return &SensitiveStore{
+		conn:   conn,
+		cipher: cipher,
+	}
+}
+
+// additionalData binds an encrypted value to the member and column it was written for.
+func additionalData(memberID uuid.UUID, column string) []byte {
+	return append(memberID[:], column...)
+}
+
+func (s *SensitiveStore) encrypt(memberID uuid.UUID, column, value string) ([]byte, error) {
+	ciphertext, err := s.cipher.Encrypt(value, additionalData(memberID, column))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s: %w", column, err)
+	}
+	return ciphertext, nil
+}
+
+func (s *SensitiveStore) decrypt(memberID uuid.UUID, column string, ciphertext []byte) (string, error) {
+	value, err := s.cipher.Decrypt(ciphertext, additionalData(memberID, column))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", column, err)
+	}
+	return value, nil
+}
+
+func (s *SensitiveStore) AddEmergencyContact(ctx context.Context, contact *model.EmergencyContact) error {
+	name, err := s.encrypt(contact.MemberID, "name", contact.Name)
+	if err != nil {
+		return err
+	}
+	phone, err := s.encrypt(contact.MemberID, "phone", contact.PhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO member_emergency_contacts (member_id, name, relationship, phone)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	if err := s.conn.QueryRow(ctx, query, contact.MemberID, name, contact.Relationship, phone).Scan(
+		&contact.ID,
+		&contact.CreatedAt,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add emergency contact: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SensitiveStore) GetEmergencyContacts(ctx context.Context, memberID uuid.UUID) ([]*model.EmergencyContact, error) {
+	query := `
+		SELECT id, member_id, name, relationship, phone, created_at
+		FROM member_emergency_contacts
+		WHERE member_id = $1
+		ORDER BY created_at
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get emergency contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []*model.EmergencyContact
+	for rows.Next() {
+		var contact model.EmergencyContact
+		var name, phone []byte
+		if err := rows.Scan(
+			&contact.ID,
+			&contact.MemberID,
+			&name,
+			&contact.Relationship,
+			&phone,
+			&contact.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan emergency contact: %w", err)
+		}
+		if contact.Name, err = s.decrypt(contact.MemberID, "name", name); err != nil {
+			return nil, err
+		}
+		if contact.PhoneNumber, err = s.decrypt(contact.MemberID, "phone", phone); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, &contact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over emergency contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+func (s *SensitiveStore) DeleteEmergencyContact(ctx context.Context, memberID, id uuid.UUID) error {
+	query := `
+		DELETE FROM member_emergency_contacts
+		WHERE id = $1 AND member_id = $2
+	`
+	result, err := s.conn.Exec(ctx, query, id, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to delete emergency contact: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrEmergencyContactNotFound
+	}
+	return nil
+}
+
+func (s *SensitiveStore) AddMedicalNote(ctx context.Context, note *model.MedicalNote) error {
+	text, err := s.encrypt(note.MemberID, "note", note.Note)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO member_medical_notes (member_id, category, note)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	if err := s.conn.QueryRow(ctx, query, note.MemberID, note.Category, text).Scan(
+		&note.ID,
+		&note.CreatedAt,
+	); err != nil {
+		switch {
+		case IsPgError(err, PgForeignKeyViolation):
+			return fmt.Errorf("%w: %w", ErrMemberNotFound, err)
+		case IsPgError(err, PgNotNullViolation):
+			return fmt.Errorf("%w: %w", ErrMissingRequiredField, err)
+		default:
+			return fmt.Errorf("failed to add medical note: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SensitiveStore) GetMedicalNotes(ctx context.Context, memberID uuid.UUID) ([]*model.MedicalNote, error) {
+	query := `
+		SELECT id, member_id, category, note, created_at
+		FROM member_medical_notes
+		WHERE member_id = $1
+		ORDER BY category, created_at
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get medical notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*model.MedicalNote
+	for rows.Next() {
+		var note model.MedicalNote
+		var text []byte
+		if err := rows.Scan(
+			&note.ID,
+			&note.MemberID,
+			&note.Category,
+			&text,
+			&note.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan medical note: %w", err)
+		}
+		if note.Note, err = s.decrypt(note.MemberID, "note", text); err != nil {
+			return nil, err
+		}
+		notes = append(notes, &note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over medical notes: %w", err)
+	}
+	return notes, nil
+}
+
+func (s *SensitiveStore) DeleteMedicalNote(ctx context.Context, memberID, id uuid.UUID) error {
+	query := `
+		DELETE FROM member_medical_notes
+		WHERE id = $1 AND member_id = $2
+	`
+	result, err := s.conn.Exec(ctx, query, id, memberID)
+	if err != nil {
+		return fmt.Errorf("failed to delete medical note: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrMedicalNoteNotFound
+	}
+	return nil
+}
+
+func (s *SensitiveStore) LogSensitiveAccess(ctx context.Context, access *model.SensitiveAccess) error {
+	query := `
+		INSERT INTO sensitive_access_log (member_id, actor, resource, action)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, accessed_at
+	`
+	if err := s.conn.QueryRow(ctx, query, access.MemberID, access.Actor, access.Resource, access.Action).Scan(
+		&access.ID,
+		&access.AccessedAt,
+	); err != nil {
+		return fmt.Errorf("failed to log sensitive access: %w", err)
+	}
+	return nil
+}
+
+func (s *SensitiveStore) GetSensitiveAccessLog(ctx context.Context, memberID uuid.UUID) ([]*model.SensitiveAccess, error) {
+	query := `
+		SELECT id, member_id, actor, resource, action, accessed_at
+		FROM sensitive_access_log
+		WHERE member_id = $1
+		ORDER BY accessed_at DESC, id DESC
+	`
+	rows, err := s.conn.Query(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sensitive access log: %w", err)
+	}
+	defer rows.Close()
+
+	var log []*model.SensitiveAccess
+	for rows.Next() {
+		var access model.SensitiveAccess
+		if err := rows.Scan(
+			&access.ID,
+			&access.MemberID,
+			&access.Actor,
+			&access.Resource,
+			&access.Action,
+			&access.AccessedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sensitive access: %w", err)
+		}
+		log = append(log, &access)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over sensitive access log: %w", err)
+	}
+	return log, nil
+}
@@ -24,13 +24,14 @@ func NewSportStore(conn *pgxpool.Pool) *SportStore {
 func (s *SportStore) AddSport(ctx context.Context, sport *model.Sport) error {
 	query := `
 		INSERT INTO sports (name, description)
-		VALUES ($1, $2) RETURNING *
+		VALUES ($1, $2) RETURNING id, name, description, created_at
 	`
 	args := []any{sport.Name, sport.Description}
 	if err := s.conn.QueryRow(ctx, query, args...).Scan(
 		&sport.ID,
 		&sport.Name,
 		&sport.Description,
+		&sport.CreatedAt,
 	); err != nil {
 		switch {
 		case IsPgError(err, PgUniqueViolation):
@@ -46,7 +47,7 @@ func (s *SportStore) AddSport(ctx context.Context, sport *model.Sport) error {
 
 func (s *SportStore) GetSportByID(ctx context.Context, id uuid.UUID) (*model.Sport, error) {
 	query := `
-		SELECT id, name, description
+		SELECT id, name, description, created_at
 		FROM sports
 		WHERE id = $1
 	`
@@ -55,6 +56,7 @@ func (s *SportStore) GetSportByID(ctx context.Context, id uuid.UUID) (*model.Spo
 		&sport.ID,
 		&sport.Name,
 		&sport.Description,
+		&sport.CreatedAt,
 	); err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
@@ -66,14 +68,21 @@ func (s *SportStore) GetSportByID(ctx context.Context, id uuid.UUID) (*model.Spo
 	return &sport, nil
 }
 
-func (s *SportStore) GetAllSports(ctx context.Context) ([]*model.Sport, error) {
+// ListSports returns the page of sports selected by opts, and the cursor of the
+// next page or nil if this is the last one.
+func (s *SportStore) ListSports(ctx context.Context, opts model.ListOptions) ([]*model.Sport, *model.Cursor, error) {
+	clause, args, err := paginate(opts, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	query := `
-		SELECT id, name, description
+		SELECT id, name, COALESCE(description, ''), created_at
 		FROM sports
-	`
-	rows, err := s.conn.Query(ctx, query)
+	` + clause
+	rows, err := s.conn.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sports: %w", err)
+		return nil, nil, fmt.Errorf("failed to get sports: %w", err)
 	}
 	defer rows.Close()
 
@@ -84,15 +93,22 @@ func (s *SportStore) GetAllSports(ctx context.Context) ([]*model.Sport, error) {
 			&sport.ID,
 			&sport.Name,
 			&sport.Description,
+			&sport.CreatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("failed to scan sport: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan sport: %w", err)
 		}
 		sports = append(sports, &sport)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate over sports: %w", err)
+		return nil, nil, fmt.Errorf("failed to iterate over sports: %w", err)
+	}
+
+	if len(sports) <= opts.Limit {
+		return sports, nil, nil
 	}
-	return sports, nil
+	sports = sports[:opts.Limit]
+	last := sports[len(sports)-1]
+	return sports, nextCursor(opts, last.Name, last.CreatedAt, last.ID), nil
 }
 
 func (s *SportStore) DeleteSport(ctx context.Context, id uuid.UUID) error {
@@ -115,7 +131,7 @@ func (s *SportStore) UpdateSport(ctx context.Context, sport *model.Sport) error
 		UPDATE sports
 		SET name = $1, description = $2
 		WHERE id = $3
-		RETURNING *
+		RETURNING id, name, description, created_at
 	`
 	args := []any{
 		sport.Name,
@@ -126,6 +142,7 @@ func (s *SportStore) UpdateSport(ctx context.Context, sport *model.Sport) error
 		&sport.ID,
 		&sport.Name,
 		&sport.Description,
+		&sport.CreatedAt,
 	); err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
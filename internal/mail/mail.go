@@ -0,0 +1,26 @@
+// Package mail sends plain text emails to members.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// write writes the message with its headers in RFC 5322 form.
+func write(w io.Writer, from string, msg Message, date time.Time) error {
+	_, err := fmt.Fprintf(w, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		from, msg.To, msg.Subject, date.Format(time.RFC1123Z), msg.Body)
+	return err
+}
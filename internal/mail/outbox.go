@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Outbox is a stand-in mailer for development and tests. Instead of sending
+// messages it writes each one to an .eml file in a directory.
+type Outbox struct {
+	dir  string
+	from string
+}
+
+// NewOutbox returns a mailer writing to dir, which is created if it does not exist.
+func NewOutbox(dir, from string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	return &Outbox{dir: dir, from: from}, nil
+}
+
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	to := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == filepath.Separator {
+			return '_'
+		}
+		return r
+	}, msg.To)
+
+	f, err := os.CreateTemp(o.dir, fmt.Sprintf("%s-%s-*.eml", now.UTC().Format("20060102T150405"), to))
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	if err := write(f, o.from, msg, now); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTP sends messages through an SMTP server, authenticating with PLAIN auth
+// when a username is given.
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTP(addr, from, username, password string) (*SMTP, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address: %w", err)
+	}
+	s := &SMTP{addr: addr, from: from}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	var buf bytes.Buffer
+	if err := write(&buf, s.from, msg, time.Now()); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type BatchStore struct {
+	mock.Mock
+}
+
+func (m *BatchStore) AddBatch(ctx context.Context, batch *model.Batch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *BatchStore) GetBatchByID(ctx context.Context, id uuid.UUID) (*model.Batch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Batch), args.Error(1)
+}
+
+func (m *BatchStore) GetBatchesBySportID(ctx context.Context, sportID uuid.UUID) ([]*model.Batch, error) {
+	args := m.Called(ctx, sportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Batch), args.Error(1)
+}
+
+func (m *BatchStore) UpdateBatch(ctx context.Context, batch *model.Batch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *BatchStore) DeleteBatch(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
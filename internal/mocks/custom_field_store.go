@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type CustomFieldStore struct {
+	mock.Mock
+}
+
+func (m *CustomFieldStore) AddCustomField(ctx context.Context, field *model.CustomField) error {
+	args := m.Called(ctx, field)
+	return args.Error(0)
+}
+
+func (m *CustomFieldStore) GetCustomFieldByID(ctx context.Context, id uuid.UUID) (*model.CustomField, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldStore) GetCustomFields(ctx context.Context) ([]*model.CustomField, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.CustomField), args.Error(1)
+}
+
+func (m *CustomFieldStore) UpdateCustomField(ctx context.Context, field *model.CustomField) error {
+	args := m.Called(ctx, field)
+	return args.Error(0)
+}
+
+func (m *CustomFieldStore) DeleteCustomField(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
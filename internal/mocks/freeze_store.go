@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type FreezeStore struct {
+	mock.Mock
+}
+
+func (m *FreezeStore) GetFreezesByMembershipID(ctx context.Context, membershipID uuid.UUID) ([]*model.Freeze, error) {
+	args := m.Called(ctx, membershipID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Freeze), args.Error(1)
+}
+
+func (m *FreezeStore) FreezeMembership(ctx context.Context, membership *model.Membership, freeze *model.Freeze, transition *model.MembershipTransition) error {
+	args := m.Called(ctx, membership, freeze, transition)
+	return args.Error(0)
+}
+
+func (m *FreezeStore) UnfreezeMembership(ctx context.Context, membership *model.Membership, freeze *model.Freeze, transition *model.MembershipTransition) error {
+	args := m.Called(ctx, membership, freeze, transition)
+	return args.Error(0)
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type HouseholdStore struct {
+	mock.Mock
+}
+
+func (m *HouseholdStore) AddHousehold(ctx context.Context, household *model.Household) error {
+	args := m.Called(ctx, household)
+	return args.Error(0)
+}
+
+func (m *HouseholdStore) GetHouseholdByID(ctx context.Context, id uuid.UUID) (*model.Household, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Household), args.Error(1)
+}
+
+func (m *HouseholdStore) GetHouseholdByMemberID(ctx context.Context, memberID uuid.UUID) (*model.Household, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Household), args.Error(1)
+}
+
+func (m *HouseholdStore) DeleteHousehold(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *HouseholdStore) AddHouseholdMember(ctx context.Context, member *model.HouseholdMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *HouseholdStore) RemoveHouseholdMember(ctx context.Context, householdID, memberID uuid.UUID) error {
+	args := m.Called(ctx, householdID, memberID)
+	return args.Error(0)
+}
+
+func (m *HouseholdStore) SetHouseholdPrimary(ctx context.Context, householdID, memberID uuid.UUID) error {
+	args := m.Called(ctx, householdID, memberID)
+	return args.Error(0)
+}
+
+func (m *HouseholdStore) GetHouseholdMemberships(ctx context.Context, householdID uuid.UUID) ([]*model.Membership, error) {
+	args := m.Called(ctx, householdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Membership), args.Error(1)
+}
+
+func (m *HouseholdStore) GetHouseholdDues(ctx context.Context, householdID uuid.UUID) ([]*model.Due, error) {
+	args := m.Called(ctx, householdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Due), args.Error(1)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type InvoiceStore struct {
+	mock.Mock
+}
+
+func (m *InvoiceStore) AddInvoice(ctx context.Context, invoice *model.Invoice) error {
+	args := m.Called(ctx, invoice)
+	return args.Error(0)
+}
+
+func (m *InvoiceStore) GetInvoiceByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Invoice), args.Error(1)
+}
+
+func (m *InvoiceStore) GetInvoicesByMemberID(ctx context.Context, memberID uuid.UUID) ([]*model.Invoice, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Invoice), args.Error(1)
+}
+
+func (m *InvoiceStore) IssueInvoice(ctx context.Context, invoice *model.Invoice, prefix string, now time.Time) error {
+	args := m.Called(ctx, invoice, prefix, now)
+	return args.Error(0)
+}
+
+func (m *InvoiceStore) UpdateInvoiceStatus(ctx context.Context, invoice *model.Invoice, status model.InvoiceStatus) error {
+	args := m.Called(ctx, invoice, status)
+	return args.Error(0)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
 	"github.com/google/uuid"
@@ -34,20 +35,150 @@ func (m *MemberStore) GetMemberByEmail(ctx context.Context, email string) (*mode
 	return args.Get(0).(*model.Member), args.Error(1)
 }
 
-func (m *MemberStore) GetAllMembers(ctx context.Context) ([]*model.Member, error) {
+func (m *MemberStore) GetMemberByPhone(ctx context.Context, phone string) (*model.Member, error) {
+	args := m.Called(ctx, phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Member), args.Error(1)
+}
+
+func (m *MemberStore) GetMemberPhones(ctx context.Context) (map[uuid.UUID]string, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
+	return args.Get(0).(map[uuid.UUID]string), args.Error(1)
+}
+
+func (m *MemberStore) UpdateMemberPhones(ctx context.Context, phones map[uuid.UUID]string) (int64, error) {
+	args := m.Called(ctx, phones)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MemberStore) ListMembers(ctx context.Context, opts model.MemberListOptions) ([]*model.Member, *model.Cursor, error) {
+	args := m.Called(ctx, opts)
+	var next *model.Cursor
+	if args.Get(1) != nil {
+		next = args.Get(1).(*model.Cursor)
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]*model.Member), next, args.Error(2)
+}
+
+func (m *MemberStore) SearchMembers(ctx context.Context, query string, limit int) ([]*model.MemberMatch, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MemberMatch), args.Error(1)
+}
+
+func (m *MemberStore) GetMemberAnniversaries(ctx context.Context, month time.Month, year int) ([]*model.Member, error) {
+	args := m.Called(ctx, month, year)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*model.Member), args.Error(1)
 }
 
-func (m *MemberStore) UpdateMember(ctx context.Context, member *model.Member) error {
-	args := m.Called(ctx, member)
+func (m *MemberStore) UpdateMember(ctx context.Context, member *model.Member, change *model.MemberStatusChange) error {
+	args := m.Called(ctx, member, change)
 	return args.Error(0)
 }
 
+func (m *MemberStore) GetMemberStatusHistory(ctx context.Context, memberID uuid.UUID) ([]*model.MemberStatusChange, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MemberStatusChange), args.Error(1)
+}
+
 func (m *MemberStore) DeleteMember(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MemberStore) RestoreMember(ctx context.Context, id uuid.UUID) (*model.Member, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Member), args.Error(1)
+}
+
+func (m *MemberStore) RequestEmailChange(ctx context.Context, change *model.EmailChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MemberStore) ConfirmEmailChange(ctx context.Context, change *model.EmailChange) (*model.Member, error) {
+	args := m.Called(ctx, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Member), args.Error(1)
+}
+
+func (m *MemberStore) FindDuplicateMembers(ctx context.Context, minSimilarity float64, limit int) ([]*model.DuplicateMatch, error) {
+	args := m.Called(ctx, minSimilarity, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.DuplicateMatch), args.Error(1)
+}
+
+func (m *MemberStore) MergeMembers(ctx context.Context, merge *model.MemberMerge) error {
+	args := m.Called(ctx, merge)
+	return args.Error(0)
+}
+
+func (m *MemberStore) GetMemberMerges(ctx context.Context, memberID uuid.UUID) ([]*model.MemberMerge, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MemberMerge), args.Error(1)
+}
+
+func (m *MemberStore) GetMemberTags(ctx context.Context, memberID uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MemberStore) AddMemberTags(ctx context.Context, memberID uuid.UUID, tags []string) error {
+	args := m.Called(ctx, memberID, tags)
+	return args.Error(0)
+}
+
+func (m *MemberStore) RemoveMemberTags(ctx context.Context, memberID uuid.UUID, tags []string) error {
+	args := m.Called(ctx, memberID, tags)
+	return args.Error(0)
+}
+
+func (m *MemberStore) GetMemberCredits(ctx context.Context, memberID uuid.UUID) ([]*model.MemberCredit, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MemberCredit), args.Error(1)
+}
+
+func (m *MemberStore) SetMemberPhoto(ctx context.Context, id uuid.UUID, updatedAt *time.Time) error {
+	args := m.Called(ctx, id, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MemberStore) PurgeMembers(ctx context.Context, archivedBefore time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, archivedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
@@ -0,0 +1,92 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MembershipStore struct {
+	mock.Mock
+}
+
+func (m *MembershipStore) AddMembership(ctx context.Context, membership *model.Membership, actor string) error {
+	args := m.Called(ctx, membership, actor)
+	return args.Error(0)
+}
+
+func (m *MembershipStore) GetMembershipByID(ctx context.Context, id uuid.UUID) (*model.Membership, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Membership), args.Error(1)
+}
+
+func (m *MembershipStore) GetAllMemberships(ctx context.Context) ([]*model.Membership, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Membership), args.Error(1)
+}
+
+func (m *MembershipStore) UpdateMembership(ctx context.Context, membership *model.Membership) error {
+	args := m.Called(ctx, membership)
+	return args.Error(0)
+}
+
+func (m *MembershipStore) TransitionMembership(ctx context.Context, membership *model.Membership, transition *model.MembershipTransition) error {
+	args := m.Called(ctx, membership, transition)
+	return args.Error(0)
+}
+
+func (m *MembershipStore) TransitionOverdueMemberships(ctx context.Context, from, to model.MembershipStatus, dueBefore time.Time, actor, reason string) (int64, error) {
+	args := m.Called(ctx, from, to, dueBefore, actor, reason)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MembershipStore) GetMembershipHistory(ctx context.Context, id uuid.UUID) ([]*model.MembershipTransition, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MembershipTransition), args.Error(1)
+}
+
+func (m *MembershipStore) GetRenewableMemberships(ctx context.Context, now time.Time) ([]*model.Membership, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Membership), args.Error(1)
+}
+
+func (m *MembershipStore) RenewMembership(ctx context.Context, current, next *model.Membership, actor string) error {
+	args := m.Called(ctx, current, next, actor)
+	return args.Error(0)
+}
+
+func (m *MembershipStore) ChangeMembership(ctx context.Context, current, next *model.Membership, transition *model.MembershipTransition, credit *model.MemberCredit) error {
+	args := m.Called(ctx, current, next, transition, credit)
+	return args.Error(0)
+}
+
+func (m *MembershipStore) GetMembershipsByMemberID(ctx context.Context, memberID uuid.UUID) ([]*model.Membership, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Membership), args.Error(1)
+}
+
+func (m *MembershipStore) GetOutstandingDues(ctx context.Context, sportID *uuid.UUID) ([]*model.Due, error) {
+	args := m.Called(ctx, sportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Due), args.Error(1)
+}
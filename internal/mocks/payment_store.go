@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type PaymentStore struct {
+	mock.Mock
+}
+
+func (m *PaymentStore) AddPayment(ctx context.Context, payment *model.Payment) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *PaymentStore) GetPaymentByID(ctx context.Context, id uuid.UUID) (*model.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Payment), args.Error(1)
+}
+
+func (m *PaymentStore) GetPaymentsByMembershipID(ctx context.Context, membershipID uuid.UUID) ([]*model.Payment, error) {
+	args := m.Called(ctx, membershipID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Payment), args.Error(1)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type PlanStore struct {
+	mock.Mock
+}
+
+func (m *PlanStore) AddPlan(ctx context.Context, plan *model.Plan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *PlanStore) GetPlanByID(ctx context.Context, id uuid.UUID) (*model.Plan, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Plan), args.Error(1)
+}
+
+func (m *PlanStore) GetPlansBySportID(ctx context.Context, sportID uuid.UUID) ([]*model.Plan, error) {
+	args := m.Called(ctx, sportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Plan), args.Error(1)
+}
+
+func (m *PlanStore) UpdatePlan(ctx context.Context, plan *model.Plan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *PlanStore) DeletePlan(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type SegmentStore struct {
+	mock.Mock
+}
+
+func (m *SegmentStore) AddSegment(ctx context.Context, segment *model.Segment) error {
+	args := m.Called(ctx, segment)
+	return args.Error(0)
+}
+
+func (m *SegmentStore) GetSegmentByID(ctx context.Context, id uuid.UUID) (*model.Segment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Segment), args.Error(1)
+}
+
+func (m *SegmentStore) GetSegments(ctx context.Context) ([]*model.Segment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Segment), args.Error(1)
+}
+
+func (m *SegmentStore) UpdateSegment(ctx context.Context, segment *model.Segment) error {
+	args := m.Called(ctx, segment)
+	return args.Error(0)
+}
+
+func (m *SegmentStore) DeleteSegment(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Ruthvik10/membership-managment-system/internal/db/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type SensitiveStore struct {
+	mock.Mock
+}
+
+func (m *SensitiveStore) AddEmergencyContact(ctx context.Context, contact *model.EmergencyContact) error {
+	args := m.Called(ctx, contact)
+	return args.Error(0)
+}
+
+func (m *SensitiveStore) GetEmergencyContacts(ctx context.Context, memberID uuid.UUID) ([]*model.EmergencyContact, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.EmergencyContact), args.Error(1)
+}
+
+func (m *SensitiveStore) DeleteEmergencyContact(ctx context.Context, memberID, id uuid.UUID) error {
+	args := m.Called(ctx, memberID, id)
+	return args.Error(0)
+}
+
+func (m *SensitiveStore) AddMedicalNote(ctx context.Context, note *model.MedicalNote) error {
+	args := m.Called(ctx, note)
+	return args.Error(0)
+}
+
+func (m *SensitiveStore) GetMedicalNotes(ctx context.Context, memberID uuid.UUID) ([]*model.MedicalNote, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MedicalNote), args.Error(1)
+}
+
+func (m *SensitiveStore) DeleteMedicalNote(ctx context.Context, memberID, id uuid.UUID) error {
+	args := m.Called(ctx, memberID, id)
+	return args.Error(0)
+}
+
+func (m *SensitiveStore) LogSensitiveAccess(ctx context.Context, access *model.SensitiveAccess) error {
+	args := m.Called(ctx, access)
+	return args.Error(0)
+}
+
+func (m *SensitiveStore) GetSensitiveAccessLog(ctx context.Context, memberID uuid.UUID) ([]*model.SensitiveAccess, error) {
+	args := m.Called(ctx, memberID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SensitiveAccess), args.Error(1)
+}
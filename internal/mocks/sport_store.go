@@ -12,9 +12,13 @@ type SportStore struct {
 	mock.Mock
 }
 
-func (s *MemberStore) GetAllSports(ctx context.Context) ([]*model.Sport, error) {
-	args := s.Called(ctx)
-	return args.Get(0).([]*model.Sport), args.Error(1)
+func (s *MemberStore) ListSports(ctx context.Context, opts model.ListOptions) ([]*model.Sport, *model.Cursor, error) {
+	args := s.Called(ctx, opts)
+	var next *model.Cursor
+	if args.Get(1) != nil {
+		next = args.Get(1).(*model.Cursor)
+	}
+	return args.Get(0).([]*model.Sport), next, args.Error(2)
 }
 
 func (s *MemberStore) GetSportByID(ctx context.Context, id uuid.UUID) (*model.Sport, error) {
@@ -3,4 +3,14 @@ package mocks
 type MockStore struct {
 	*MemberStore
 	*SportStore
+	*MembershipStore
+	*PaymentStore
+	*PlanStore
+	*InvoiceStore
+	*FreezeStore
+	*HouseholdStore
+	*SensitiveStore
+	*CustomFieldStore
+	*SegmentStore
+	*BatchStore
 }
@@ -0,0 +1,161 @@
+// Package pdf writes simple text-only PDF documents using the standard
+// Helvetica fonts, without any external dependencies.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+type text struct {
+	x, y float64
+	size float64
+	font Font
+	s    string
+}
+
+type line struct {
+	x1, y1, x2, y2 float64
+}
+
+type page struct {
+	texts []text
+	lines []line
+}
+
+// Document is a PDF document made of pages of text and lines. Coordinates are
+// in points from the top-left corner of the page.
+type Document struct {
+	pages []*page
+}
+
+// New returns a document with a single empty page.
+func New() *Document {
+	return &Document{pages: []*page{{}}}
+}
+
+// AddPage starts a new page. Subsequent drawing goes to the new page.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &page{})
+}
+
+// Text draws s with its baseline at (x, y).
+func (d *Document) Text(x, y, size float64, font Font, s string) {
+	p := d.pages[len(d.pages)-1]
+	p.texts = append(p.texts, text{x: x, y: y, size: size, font: font, s: s})
+}
+
+// TextRight draws s so that it ends at x.
+func (d *Document) TextRight(x, y, size float64, font Font, s string) {
+	d.Text(x-TextWidth(s, size, font), y, size, font, s)
+}
+
+// Line draws a thin line from (x1, y1) to (x2, y2).
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	p := d.pages[len(d.pages)-1]
+	p.lines = append(p.lines, line{x1: x1, y1: y1, x2: x2, y2: y2})
+}
+
+// TextWidth approximates the width of s in points. Helvetica averages a little
+// over half the font size per character, bold slightly more.
+func TextWidth(s string, size float64, font Font) float64 {
+	factor := 0.52
+	if font == Bold {
+		factor = 0.56
+	}
+	return float64(len([]rune(s))) * size * factor
+}
+
+// WriteTo writes the document in PDF format to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-4 are the catalog, the page tree and the two fonts. Each page
+	// then takes two objects: the page itself and its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+2*i,
+		))
+		content := p.content()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+func (p *page) content() string {
+	var b strings.Builder
+	for _, l := range p.lines {
+		fmt.Fprintf(&b, "0.5 w %.2f %.2f m %.2f %.2f l S\n", l.x1, PageHeight-l.y1, l.x2, PageHeight-l.y2)
+	}
+	for _, t := range p.texts {
+		font := "F1"
+		if t.font == Bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&b, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, t.size, t.x, PageHeight-t.y, escape(t.s))
+	}
+	return b.String()
+}
+
+// escape encodes s as the contents of a PDF literal string. Characters outside
+// Latin-1 cannot be shown by the standard fonts and are replaced with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
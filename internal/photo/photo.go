@@ -0,0 +1,138 @@
+// Package photo validates uploaded member photos and re-encodes them as JPEG in
+// a display and a thumbnail size. Re-encoding also drops any metadata, such as
+// the location, embedded in the upload.
+package photo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// ContentType is the type of the processed images.
+const ContentType = "image/jpeg"
+
+const (
+	// MaxPixels bounds the decoded size of an upload, as a small file can hold a huge image.
+	MaxPixels = 25_000_000
+
+	DisplaySize   = 1024
+	ThumbnailSize = 256
+
+	quality = 85
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooManyPixels   = errors.New("image has too many pixels")
+)
+
+// SupportedTypes lists the content types accepted for upload.
+var SupportedTypes = []string{"image/jpeg", "image/png"}
+
+// Photo holds the processed images of an upload.
+type Photo struct {
+	Display   []byte
+	Thumbnail []byte
+}
+
+// Process decodes the upload and returns the display image, fit within
+// DisplaySize, and a square thumbnail of ThumbnailSize cropped from its centre.
+// The type is sniffed from the data rather than trusted from the client.
+func Process(data []byte) (*Photo, error) {
+	contentType := http.DetectContentType(data)
+	supported := false
+	for _, t := range SupportedTypes {
+		supported = supported || t == contentType
+	}
+	if !supported {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedType, err)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedType, err)
+	}
+
+	bounds := img.Bounds()
+	w, h := fit(bounds.Dx(), bounds.Dy(), DisplaySize)
+	display, err := encode(resize(img, bounds, w, h))
+	if err != nil {
+		return nil, err
+	}
+
+	side := min(bounds.Dx(), bounds.Dy())
+	square := image.Rect(0, 0, side, side).Add(bounds.Min).Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+	w, h = fit(side, side, ThumbnailSize)
+	thumbnail, err := encode(resize(img, square, w, h))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Photo{Display: display, Thumbnail: thumbnail}, nil
+}
+
+// fit scales w and h down to fit within size, keeping the aspect ratio. Smaller images are left as is.
+func fit(w, h, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+	if w >= h {
+		return size, max(1, h*size/w)
+	}
+	return max(1, w*size/h), size
+}
+
+// resize averages the pixels of r in src into a w by h image, flattening any
+// transparency onto white as JPEG has no alpha channel.
+func resize(src image.Image, r image.Rectangle, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := r.Min.Y + y*r.Dy()/h
+		y1 := max(r.Min.Y+(y+1)*r.Dy()/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := r.Min.X + x*r.Dx()/w
+			x1 := max(r.Min.X+(x+1)*r.Dx()/w, x0+1)
+
+			var rs, gs, bs, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					white := uint64(0xffff - ca)
+					rs += uint64(cr) + white
+					gs += uint64(cg) + white
+					bs += uint64(cb) + white
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(rs / n),
+				G: uint16(gs / n),
+				B: uint16(bs / n),
+				A: 0xffff,
+			})
+		}
+	}
+	return dst
+}
+
+func encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode photo: %w", err)
+	}
+	return buf.Bytes(), nil
+}